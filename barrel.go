@@ -0,0 +1,422 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	CheckpointFlashTicks = 40
+)
+
+// Barrel est le baril de base : le joueur y atterrit puis en est lancé.
+type Barrel struct {
+	X, Y, W, H float64
+	Role       ColorRole
+	Goal       bool    // atterrir ici termine le niveau
+	Angle      float64 // direction de tir en radians, 0 = vers la droite
+	RotSpeed   float64 // rotation du canon en radians par tick
+	AutoFire   int     // délai en ticks avant l'éjection automatique, 0 = tir avec Espace
+	Path       *Path   // nil = immobile
+	vx, vy     float64 // déplacement du dernier tick
+}
+
+func NewBarrel(x, y float64) *Barrel {
+	return &Barrel{X: x, Y: y, W: 100, H: 50, Role: RoleBarrel}
+}
+
+func NewGoalBarrel(x, y float64) *Barrel {
+	b := NewBarrel(x, y)
+	b.Goal = true
+	return b
+}
+
+func (b *Barrel) WithPath(p *Path) *Barrel {
+	b.Path = p
+	return b
+}
+
+func (b *Barrel) barrel() *Barrel {
+	return b
+}
+
+// Directional indique un baril canon qui tire dans une autre direction que la droite.
+func (b *Barrel) Directional() bool {
+	return b.Angle != 0 || b.RotSpeed != 0
+}
+
+// Cannon indique un baril qui garde le joueur en son centre avant de le lancer.
+func (b *Barrel) Cannon() bool {
+	return b.Directional() || b.AutoFire > 0
+}
+
+// SpawnPoint renvoie où le joueur réapparaît dans ce baril : au centre d'un
+// canon, sinon au bord droit comme au départ d'un niveau.
+func (b *Barrel) SpawnPoint() (float64, float64) {
+	if b.Cannon() {
+		return b.X + b.W/2, b.Y + b.H/2
+	}
+	return b.X + 110, b.Y + 25
+}
+
+// Velocity renvoie le déplacement du baril au dernier tick.
+func (b *Barrel) Velocity() (float64, float64) {
+	return b.vx, b.vy
+}
+
+func (b *Barrel) Touches(g *Game) bool {
+	return CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, b.X, b.Y, b.W, b.H)
+}
+
+func (b *Barrel) Update(g *Game) {
+	// --- Barils qui suivent un chemin ---
+	if b.Path != nil {
+		x, y := b.Path.Step(b.X, b.Y, g.TimeScale())
+		b.vx, b.vy = x-b.X, y-b.Y
+		b.X, b.Y = x, y
+
+		// le joueur assis dans le baril est emporté avec lui
+		if g.Rider() == b {
+			g.PlayerX += b.vx
+			g.PlayerY += b.vy
+		}
+	}
+	// --- Barils canons qui tournent ---
+	if b.RotSpeed != 0 {
+		b.Angle += b.RotSpeed * g.TimeScale()
+	}
+}
+
+func (b *Barrel) CollidePlayer(g *Game) {
+	b.land(g, false)
+}
+
+// land fait atterrir le joueur lancé dans le baril. keepSlowMotion garde le
+// slow motion actif (barils magiques).
+func (b *Barrel) land(g *Game, keepSlowMotion bool) {
+	if !g.PlayerMoved || !g.CanLandIn(b) {
+		return
+	}
+	x, y, w, h := g.LandingRect(b)
+	if !CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, x, y, w, h) {
+		return
+	}
+	g.PlayerMoved = false
+	g.PlayerBarrel = b
+	g.PlayerVX, g.PlayerVY = 0, 0
+	// dans un canon, le joueur se place au centre pour viser
+	if b.Cannon() {
+		g.PlayerX = b.X + b.W/2
+		g.PlayerY = b.Y + b.H/2
+	}
+	g.AutoFireTimer = float64(b.AutoFire)
+	g.Emit(&LandingEmitter, g.PlayerX, g.PlayerY, 0)
+	if !keepSlowMotion {
+		g.SlowMotion = false
+		g.PlayerSpeed = LaunchSpeed
+	}
+	// --- CHANGER DE NIVEAU ---
+	if b.Goal && !g.ChangeLevelAnimation {
+		g.RecordCollectibles(g.Level)
+		g.Level++
+		g.SlowMotion = false
+		g.WinSound.Rewind()
+		g.WinSound.Play()
+		g.Emit(&LevelClearEmitter, b.X+b.W/2, b.Y, 0)
+		g.ChangeLevelAnimation = true
+	}
+}
+
+func (b *Barrel) SweepRect(g *Game) (float64, float64, float64, float64, bool) {
+	if !g.CanLandIn(b) {
+		return 0, 0, 0, 0, false
+	}
+	x, y, w, h := g.LandingRect(b)
+	return x, y, w, h, true
+}
+
+func (b *Barrel) Draw(screen *ebiten.Image, g *Game) {
+	clr := RoleColor(b.Role)
+	if !b.Cannon() {
+		if !DrawSprite(screen, "barrel", g.AnimTick, b.X, b.Y, b.W, b.H, 0, clr) {
+			ebitenutil.DrawRect(screen, b.X, b.Y, b.W, b.H, clr)
+		}
+		// damier d'arrivée
+		if b.Goal {
+			DrawChecker(screen, b.X+b.W-20, b.Y, 20, b.H, 10, RoleColor(RoleCue))
+		}
+		return
+	}
+	if !DrawSprite(screen, "barrel", g.AnimTick, b.X, b.Y, b.W, b.H, b.Angle, clr) {
+		DrawRotatedRect(screen, b.X, b.Y, b.W, b.H, b.Angle, clr)
+	}
+	// bouche du canon
+	cx, cy := b.X+b.W/2, b.Y+b.H/2
+	ebitenutil.DrawCircle(screen, cx+math.Cos(b.Angle)*b.W/2, cy+math.Sin(b.Angle)*b.W/2, 8, color.RGBA{90, 45, 12, 255})
+	if b != g.PlayerBarrel || g.PlayerMoved {
+		return
+	}
+	// compte à rebours du tir automatique
+	if b.AutoFire > 0 {
+		DrawCountdownRing(screen, cx, cy, 40, g.AutoFireTimer/float64(b.AutoFire), color.RGBA{255, 215, 0, 255})
+	}
+	// viseur quand le joueur est dans le canon
+	for d := 70.0; d <= 150; d += 20 {
+		ebitenutil.DrawCircle(screen, cx+math.Cos(b.Angle)*d, cy+math.Sin(b.Angle)*d, 3, color.RGBA{255, 255, 255, 200})
+	}
+}
+
+func (b *Barrel) Serialize() EntityData {
+	return serializeEntity("barrel", b)
+}
+
+// MagicBarrel déclenche le slow motion quand le joueur le touche.
+type MagicBarrel struct {
+	*Barrel
+}
+
+func NewMagicBarrel(x, y float64) *MagicBarrel {
+	b := NewBarrel(x, y)
+	b.Role = RoleMagicBarrel
+	return &MagicBarrel{b}
+}
+
+func (m *MagicBarrel) CollidePlayer(g *Game) {
+	if m.Touches(g) {
+		if !g.slowMotionAnimation {
+			g.slowMotionAnimation = true
+			g.slowMotionCooldown = 60
+		}
+		if !g.ChangeLevelAnimation {
+			if !g.SlowMotion {
+				g.slowMotionSound.Rewind()
+				g.slowMotionSound.Play()
+				g.Emit(&SlowMotionEmitter, g.PlayerX, g.PlayerY, 0)
+			}
+			g.SlowMotion = true
+			g.SetPlayerSpeed(6) // Vitesse réduite, même direction
+		}
+	}
+	m.land(g, true)
+}
+
+func (m *MagicBarrel) Draw(screen *ebiten.Image, g *Game) {
+	m.Barrel.Draw(screen, g)
+	DrawStripes(screen, m.X, m.Y, m.W, m.H, 12, RoleColor(RoleCue))
+}
+
+func (m *MagicBarrel) Serialize() EntityData {
+	return serializeEntity("magic", m)
+}
+
+// BreakConsequence est ce qui arrive quand un baril fragile casse.
+type BreakConsequence int
+
+const (
+	BreakLevelDown BreakConsequence = iota // retour au niveau précédent
+	BreakFall                              // le joueur tombe dans le vide
+	BreakNothing                           // le baril disparaît, rien de plus
+)
+
+// FragileCrackStages est le nombre de fissures visibles avant l'explosion.
+const FragileCrackStages = 4
+
+// FragileBarrel craque puis explose si le joueur y reste trop longtemps.
+// CoolDown est la solidité restante, sur Durability ticks de contact.
+// Avec Respawn > 0, le baril réapparaît Respawn secondes après avoir cassé.
+type FragileBarrel struct {
+	*Barrel
+	CoolDown   int
+	Durability int
+	Respawn    float64
+	OnBreak    BreakConsequence
+	broken     bool
+	respawn    float64
+}
+
+func NewFragileBarrel(x, y float64) *FragileBarrel {
+	return &FragileBarrel{Barrel: NewBarrel(x, y), CoolDown: 100, Durability: 100}
+}
+
+func (f *FragileBarrel) WithRespawn(seconds float64) *FragileBarrel {
+	f.Respawn = seconds
+	return f
+}
+
+func (f *FragileBarrel) WithBreak(c BreakConsequence) *FragileBarrel {
+	f.OnBreak = c
+	return f
+}
+
+// CrackStage renvoie le nombre de fissures, de 0 (intact) à FragileCrackStages.
+func (f *FragileBarrel) CrackStage() int {
+	durability := f.Durability
+	if durability <= 0 {
+		durability = 100
+	}
+	return min(FragileCrackStages, (durability-f.CoolDown)*(FragileCrackStages+1)/durability)
+}
+
+func (f *FragileBarrel) Update(g *Game) {
+	// cassé, il continue son chemin pour réapparaître au bon endroit
+	f.Barrel.Update(g)
+	if f.broken {
+		f.respawn -= g.TimeScale()
+		if f.respawn <= 0 {
+			f.broken = false
+			f.CoolDown = f.Durability
+			f.Role = RoleBarrel
+		}
+		return
+	}
+	if !f.Touches(g) {
+		f.Role = RoleBarrel
+	}
+}
+
+func (f *FragileBarrel) CollidePlayer(g *Game) {
+	if f.broken {
+		return
+	}
+	if f.Touches(g) {
+		stage := f.CrackStage()
+		f.CoolDown--
+		f.Role = RoleFragileHot
+		if f.CrackStage() > stage && f.CoolDown > 0 {
+			g.PlayAt(g.crackSound, f.X+f.W/2)
+		}
+		if f.CoolDown <= 0 && !g.ChangeLevelAnimation {
+			f.explode(g)
+			return
+		}
+	}
+	f.land(g, false)
+}
+
+func (f *FragileBarrel) explode(g *Game) {
+	g.PlayAt(g.ExplosionSound, f.X+f.W/2)
+	g.Emit(&ExplosionEmitter, f.X+f.W/2, f.Y+f.H/2, 0)
+	g.Shake(0.6)
+	inside := g.PlayerBarrel == f.Barrel && !g.PlayerMoved
+	switch f.OnBreak {
+	case BreakLevelDown:
+		g.TimeBeforeLevelDown = 45
+	case BreakFall:
+		if inside {
+			// plus rien sous le joueur : il tombe hors de l'écran
+			g.PlayerMoved = true
+			g.PlayerLeftBarrel = true
+			g.PlayerVX, g.PlayerVY = 0, LaunchSpeed/2
+			g.PlayerSpeed = LaunchSpeed / 2
+		}
+	}
+	if f.Respawn <= 0 {
+		g.RemoveEntity(f)
+		return
+	}
+	f.broken = true
+	f.respawn = f.Respawn * 60
+	if g.PlayerBarrel == f.Barrel {
+		g.PlayerBarrel = nil
+	}
+}
+
+func (f *FragileBarrel) SweepRect(g *Game) (float64, float64, float64, float64, bool) {
+	if f.broken {
+		return 0, 0, 0, 0, false
+	}
+	return f.Barrel.SweepRect(g)
+}
+
+func (f *FragileBarrel) Draw(screen *ebiten.Image, g *Game) {
+	if f.broken {
+		// contour pointillé en attendant la réapparition
+		DrawDashedRect(screen, f.X, f.Y, f.W, f.H, RoleColor(RoleBarrel))
+		return
+	}
+	f.Barrel.Draw(screen, g)
+	DrawDashedRect(screen, f.X+4, f.Y+4, f.W-8, f.H-8, RoleColor(RoleCue))
+	// une fissure de plus à chaque étape
+	for _, c := range fragileCracks[:f.CrackStage()] {
+		for i := 0; i+3 < len(c); i += 2 {
+			vector.StrokeLine(screen,
+				float32(f.X+c[i]*f.W), float32(f.Y+c[i+1]*f.H),
+				float32(f.X+c[i+2]*f.W), float32(f.Y+c[i+3]*f.H),
+				2, color.Black, true)
+		}
+	}
+}
+
+// fragileCracks sont les lignes brisées des fissures, en fractions du baril.
+var fragileCracks = [FragileCrackStages][]float64{
+	{0.30, 0, 0.35, 0.40, 0.28, 0.70},
+	{0.70, 1, 0.62, 0.55, 0.72, 0.30, 0.66, 0},
+	{0, 0.45, 0.20, 0.50, 0.35, 0.40, 0.50, 0.60},
+	{0.50, 0.60, 0.80, 0.50, 1, 0.65},
+}
+
+func (f *FragileBarrel) Serialize() EntityData {
+	return serializeEntity("fragile", f)
+}
+
+// CheckpointBarrel devient le point de réapparition du niveau une fois touché.
+type CheckpointBarrel struct {
+	*Barrel
+	Active bool
+	flash  float64
+}
+
+func NewCheckpointBarrel(x, y float64) *CheckpointBarrel {
+	return &CheckpointBarrel{Barrel: NewBarrel(x, y)}
+}
+
+func (c *CheckpointBarrel) Update(g *Game) {
+	c.Barrel.Update(g)
+	if c.flash > 0 {
+		c.flash -= g.TimeScale()
+	}
+}
+
+func (c *CheckpointBarrel) CollidePlayer(g *Game) {
+	if !c.Active && c.Touches(g) {
+		// un seul checkpoint actif à la fois
+		for _, e := range g.Entities {
+			if other, ok := e.(*CheckpointBarrel); ok {
+				other.Active = false
+			}
+		}
+		c.Active = true
+		c.flash = CheckpointFlashTicks
+		g.SpawnBarrel = c.Barrel
+		x, y := c.SpawnPoint()
+		g.Emit(&CheckpointEmitter, x, y, 0)
+		g.PlayAt(g.NameConfirmSound, x)
+	}
+	c.land(g, false)
+}
+
+func (c *CheckpointBarrel) Draw(screen *ebiten.Image, g *Game) {
+	c.Barrel.Draw(screen, g)
+	// drapeau sur le baril
+	clr := color.RGBA{150, 150, 150, 255}
+	if c.Active {
+		clr = RoleColor(RoleCheckpoint)
+	}
+	ebitenutil.DrawRect(screen, c.X+10, c.Y-30, 3, 30, color.RGBA{220, 220, 220, 255})
+	ebitenutil.DrawRect(screen, c.X+13, c.Y-30, 20, 12, clr)
+	// onde d'activation
+	if c.flash > 0 {
+		t := 1 - c.flash/CheckpointFlashTicks
+		ring := RoleColor(RoleCheckpoint)
+		ring.A = uint8(255 * (1 - t))
+		vector.StrokeCircle(screen, float32(c.X+c.W/2), float32(c.Y+c.H/2), float32(30+90*t), 3, ring, true)
+	}
+}
+
+func (c *CheckpointBarrel) Serialize() EntityData {
+	return serializeEntity("checkpoint", c)
+}
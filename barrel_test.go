@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+// TestOnePressLeavesStartBarrel vérifie qu'un seul appui sur Launch fait
+// sortir le joueur du baril de départ du niveau 1.
+func TestOnePressLeavesStartBarrel(t *testing.T) {
+	g := &Game{Level: 1, PlayerSpeed: LaunchSpeed}
+	g.barrelShootSound = audioContext.NewPlayerFromBytes(nil)
+	g.RaceStartSound = audioContext.NewPlayerFromBytes(nil)
+	g.StartLevel()
+	start := g.PlayerBarrel
+	if start == nil {
+		t.Fatal("player does not start in a barrel")
+	}
+	g.Launch()
+	for range 10 {
+		g.StepPlayer()
+	}
+	if !g.PlayerMoved {
+		t.Fatalf("player landed again at x=%v", g.PlayerX)
+	}
+	if g.PlayerX-PlayerR <= start.X+start.W {
+		t.Fatalf("player still at x=%v", g.PlayerX)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	// BouncerSquashTicks est la durée de l'animation d'écrasement d'un bouncer.
+	BouncerSquashTicks = 12
+)
+
+// Bouncer renvoie le joueur en réfléchissant sa vitesse sur sa surface.
+// Avec Angle = 0, la surface fait face à la gauche.
+type Bouncer struct {
+	X, Y, W, H  float64
+	Angle       float64 // rotation du bouncer en radians
+	Restitution float64 // 1 = rebond parfait, plus = ressort
+	Path        *Path
+	squash      float64 // ticks restants de l'écrasement
+}
+
+func NewBouncer(x, y float64) *Bouncer {
+	return &Bouncer{X: x, Y: y, W: 50, H: 50, Restitution: 1}
+}
+
+func (b *Bouncer) WithPath(p *Path) *Bouncer {
+	b.Path = p
+	return b
+}
+
+// Normal renvoie la normale unitaire de la surface de rebond.
+func (b *Bouncer) Normal() (float64, float64) {
+	return -math.Cos(b.Angle), -math.Sin(b.Angle)
+}
+
+// ContactNormal renvoie la normale unitaire du bouncer au point le plus
+// proche de (x, y), sur la face touchée, comme pour un rectangle tourné.
+func (b *Bouncer) ContactNormal(x, y float64) (float64, float64) {
+	mx, my := b.X+b.W/2, b.Y+b.H/2
+	sin, cos := math.Sincos(b.Angle)
+	// position dans le repère du bouncer
+	lx := (x-mx)*cos + (y-my)*sin
+	ly := -(x-mx)*sin + (y-my)*cos
+	nx := lx - math.Max(-b.W/2, math.Min(lx, b.W/2))
+	ny := ly - math.Max(-b.H/2, math.Min(ly, b.H/2))
+	if nx == 0 && ny == 0 {
+		// centre dans le bouncer : on sort par la face la plus proche
+		if b.W/2-math.Abs(lx) < b.H/2-math.Abs(ly) {
+			nx = math.Copysign(1, lx)
+		} else {
+			ny = math.Copysign(1, ly)
+		}
+	}
+	d := math.Hypot(nx, ny)
+	nx, ny = nx/d, ny/d
+	return nx*cos - ny*sin, nx*sin + ny*cos
+}
+
+func (b *Bouncer) Update(g *Game) {
+	if b.Path != nil {
+		b.X, b.Y = b.Path.Step(b.X, b.Y, g.TimeScale())
+	}
+	if b.squash > 0 {
+		b.squash = max(b.squash-g.TimeScale(), 0)
+	}
+}
+
+func (b *Bouncer) CollidePlayer(g *Game) {
+	if !CircleOBBCollision(g.PlayerX, g.PlayerY, PlayerR, b.X, b.Y, b.W, b.H, b.Angle) {
+		return
+	}
+	nx, ny := b.ContactNormal(g.PlayerX, g.PlayerY)
+	dot := g.PlayerVX*nx + g.PlayerVY*ny
+	if dot >= 0 {
+		// le joueur s'éloigne déjà de la surface
+		return
+	}
+	// réflexion : v' = v - (1+e)(v.n)n, seule la face de rebond fait ressort
+	e := 1.0
+	if fx, fy := b.Normal(); nx*fx+ny*fy > 0.99 {
+		e = b.Restitution
+	}
+	k := (1 + e) * dot
+	g.PlayerVX -= k * nx
+	g.PlayerVY -= k * ny
+	g.PlayerSpeed = math.Hypot(g.PlayerVX, g.PlayerVY)
+	b.squash = BouncerSquashTicks
+	g.Shake(0.2)
+	g.Emit(&BounceEmitter, g.PlayerX-nx*PlayerR, g.PlayerY-ny*PlayerR, math.Atan2(g.PlayerVY, g.PlayerVX))
+	if g.BouncerSoundCooldown <= 0 {
+		g.PlayAt(g.BouncerSound, b.X+b.W/2)
+		g.BouncerSoundCooldown = 25
+	}
+}
+
+// SweepOBB renvoie le rectangle tourné du bouncer, le même que celui de
+// CollidePlayer.
+func (b *Bouncer) SweepOBB(g *Game) (float64, float64, float64, float64, float64, bool) {
+	return b.X, b.Y, b.W, b.H, b.Angle, true
+}
+
+func (b *Bouncer) Draw(screen *ebiten.Image, g *Game) {
+	// écrasement le long de la normale, comme un ressort
+	s := 1 - 0.35*math.Sin(math.Pi*b.squash/BouncerSquashTicks)
+	w := b.W * s
+	clr := RoleColor(RoleBouncer)
+	if !DrawSprite(screen, "bouncer", g.AnimTick, b.X+(b.W-w)/2, b.Y, w, b.H, b.Angle, clr) {
+		DrawRotatedRect(screen, b.X+(b.W-w)/2, b.Y, w, b.H, b.Angle, clr)
+	}
+	// chevron vers le côté qui renvoie le joueur
+	nx, ny := b.Normal()
+	cx, cy := b.X+b.W/2+nx*8, b.Y+b.H/2+ny*8
+	for _, side := range []float64{-1, 1} {
+		vector.StrokeLine(screen, float32(cx+nx*8), float32(cy+ny*8), float32(cx-ny*side*10), float32(cy+nx*side*10), 3, RoleColor(RoleCue), true)
+	}
+}
+
+func (b *Bouncer) Serialize() EntityData {
+	return serializeEntity("bouncer", b)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestBouncerHitFromBehind vérifie qu'un joueur qui touche le dos d'un
+// bouncer est renvoyé au lieu de le traverser.
+func TestBouncerHitFromBehind(t *testing.T) {
+	for _, angle := range []float64{0, math.Pi / 4, -3 * math.Pi / 4} {
+		b := NewBouncer(300, 200)
+		b.Angle = angle
+		// derrière le bouncer, le long de l'opposé de sa normale, au contact
+		fx, fy := b.Normal()
+		cx, cy := b.X+b.W/2, b.Y+b.H/2
+		d := b.W/2 + PlayerR - 1
+		g := &Game{PlayerX: cx - fx*d, PlayerY: cy - fy*d, BouncerSoundCooldown: 1}
+		g.PlayerVX, g.PlayerVY = fx*10, fy*10
+		b.CollidePlayer(g)
+		if g.PlayerVX*fx+g.PlayerVY*fy >= 0 {
+			t.Errorf("angle %v: player still moving into the bouncer (%v, %v)", angle, g.PlayerVX, g.PlayerVY)
+		}
+	}
+}
+
+func TestBouncerContactNormalFront(t *testing.T) {
+	b := NewBouncer(300, 200)
+	b.Angle = math.Pi / 4
+	fx, fy := b.Normal()
+	nx, ny := b.ContactNormal(b.X+b.W/2+fx*40, b.Y+b.H/2+fy*40)
+	if math.Abs(nx-fx) > 1e-9 || math.Abs(ny-fy) > 1e-9 {
+		t.Fatalf("got (%v, %v), want (%v, %v)", nx, ny, fx, fy)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	ScreenW = 640
+	ScreenH = 480
+
+	// SlowMotionZoom rapproche la caméra pendant le slow motion.
+	SlowMotionZoom = 1.15
+
+	MaxShakeOffset = 12   // px au trauma maximal
+	MaxShakeAngle  = 0.04 // radians au trauma maximal
+	TraumaDecay    = 0.02 // par tick
+)
+
+// Camera suit le joueur dans les limites du niveau. Le monde est dessiné
+// dans une image de la taille du niveau, puis posé sur l'écran avec le
+// zoom et le tremblement.
+type Camera struct {
+	X, Y       float64 // centre de la vue dans le niveau
+	Zoom       float64
+	TargetZoom float64
+	Trauma     float64 // 0 à 1, le tremblement varie comme son carré
+
+	shakeX, shakeY, shakeAngle float64
+	world                      *ebiten.Image
+}
+
+// Shake ajoute du trauma à la caméra (explosion, choc, rebond...).
+func (g *Game) Shake(trauma float64) {
+	g.Camera.Trauma = math.Min(1, g.Camera.Trauma+trauma)
+}
+
+// target renvoie le point que la caméra veut centrer, un peu devant le
+// joueur lancé pour voir où il va.
+func (c *Camera) target(g *Game) (float64, float64) {
+	x, y := g.PlayerX, g.PlayerY
+	if g.PlayerMoved {
+		x += g.PlayerVX * 6
+		y += g.PlayerVY * 6
+	}
+	return x, y
+}
+
+// clamp garde la vue dans le niveau, ou la centre si le niveau est plus petit.
+func (c *Camera) clamp(g *Game) {
+	hw, hh := ScreenW/2/c.Zoom, ScreenH/2/c.Zoom
+	if g.LevelW <= 2*hw {
+		c.X = g.LevelW / 2
+	} else {
+		c.X = math.Max(hw, math.Min(g.LevelW-hw, c.X))
+	}
+	if g.LevelH <= 2*hh {
+		c.Y = g.LevelH / 2
+	} else {
+		c.Y = math.Max(hh, math.Min(g.LevelH-hh, c.Y))
+	}
+}
+
+// Snap place la caméra sur le joueur sans transition (début de niveau).
+func (c *Camera) Snap(g *Game) {
+	if c.TargetZoom == 0 {
+		c.TargetZoom = 1
+	}
+	c.Zoom = c.TargetZoom
+	c.X, c.Y = c.target(g)
+	c.clamp(g)
+}
+
+func (c *Camera) Update(g *Game) {
+	c.TargetZoom = 1
+	if g.SlowMotion {
+		c.TargetZoom = SlowMotionZoom
+	}
+	c.Zoom += (c.TargetZoom - c.Zoom) * 0.05
+	tx, ty := c.target(g)
+	c.X += (tx - c.X) * 0.1
+	c.Y += (ty - c.Y) * 0.1
+	c.clamp(g)
+
+	c.Trauma = math.Max(0, c.Trauma-TraumaDecay)
+	shake := c.Trauma * c.Trauma
+	c.shakeX = MaxShakeOffset * shake * (rand.Float64()*2 - 1)
+	c.shakeY = MaxShakeOffset * shake * (rand.Float64()*2 - 1)
+	c.shakeAngle = MaxShakeAngle * shake * (rand.Float64()*2 - 1)
+}
+
+// World renvoie l'image vide où dessiner le niveau pour cette frame.
+func (c *Camera) World(g *Game) *ebiten.Image {
+	w, h := int(g.LevelW), int(g.LevelH)
+	if c.world == nil || c.world.Bounds().Dx() != w || c.world.Bounds().Dy() != h {
+		if c.world != nil {
+			c.world.Deallocate()
+		}
+		c.world = ebiten.NewImage(w, h)
+	}
+	c.world.Clear()
+	return c.world
+}
+
+// Draw pose le monde sur l'écran vu par la caméra.
+func (c *Camera) Draw(screen *ebiten.Image) {
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-c.X, -c.Y)
+	op.GeoM.Scale(c.Zoom, c.Zoom)
+	op.GeoM.Rotate(c.shakeAngle)
+	op.GeoM.Translate(ScreenW/2+c.shakeX, ScreenH/2+c.shakeY)
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(c.world, op)
+}
+
+// ScreenX convertit une position x du niveau en position x à l'écran.
+func (c *Camera) ScreenX(x float64) float64 {
+	zoom := c.Zoom
+	if zoom == 0 {
+		zoom = 1
+	}
+	return (x-c.X)*zoom + ScreenW/2
+}
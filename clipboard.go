@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// clipboardCommands sont les outils système qui écrivent le presse-papiers
+// sur leur sortie, essayés dans l'ordre. Ebiten ne donne pas accès au
+// presse-papiers.
+var clipboardCommands = map[string][][]string{
+	"windows": {{"powershell", "-NoProfile", "-Command", "Get-Clipboard"}},
+	"darwin":  {{"pbpaste"}},
+	"linux":   {{"wl-paste", "--no-newline"}, {"xclip", "-out", "-selection", "clipboard"}, {"xsel", "--output", "--clipboard"}},
+}
+
+// clipboardTimeout borne l'attente d'un outil qui ne répond pas.
+const clipboardTimeout = 2 * time.Second
+
+// ReadClipboard renvoie le texte du presse-papiers. Lancer un outil système
+// prend du temps : depuis Update, passer par ReadClipboardAsync.
+func ReadClipboard() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), clipboardTimeout)
+	defer cancel()
+	for _, cmd := range clipboardCommands[runtime.GOOS] {
+		c := exec.CommandContext(ctx, cmd[0], cmd[1:]...)
+		hideWindow(c)
+		out, err := c.Output()
+		if err == nil {
+			return strings.TrimRight(string(out), "\r\n"), nil
+		}
+	}
+	return "", errors.New("clipboard not available on " + runtime.GOOS)
+}
+
+// Paste est le résultat d'une lecture du presse-papiers.
+type Paste struct {
+	Text string
+	Err  error
+}
+
+// ReadClipboardAsync lit le presse-papiers sans bloquer le jeu : le résultat
+// arrive sur le canal, à lire lors d'un tick suivant.
+func ReadClipboardAsync() <-chan Paste {
+	ch := make(chan Paste, 1)
+	go func() {
+		s, err := ReadClipboard()
+		ch <- Paste{s, err}
+	}()
+	return ch
+}
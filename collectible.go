@@ -0,0 +1,77 @@
+package main
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+type CollectibleKind int
+
+const (
+	CollectibleCoin CollectibleKind = iota
+	CollectibleStar
+)
+
+// Collectible est un objet facultatif ramassé au contact du joueur.
+// X et Y sont le centre de l'objet.
+type Collectible struct {
+	X, Y, R float64
+	Kind    CollectibleKind
+	phase   float64
+}
+
+func NewCoin(x, y float64) *Collectible {
+	return &Collectible{X: x, Y: y, R: 10, Kind: CollectibleCoin}
+}
+
+func NewStar(x, y float64) *Collectible {
+	return &Collectible{X: x, Y: y, R: 14, Kind: CollectibleStar}
+}
+
+func (c *Collectible) Update(g *Game) {
+	c.phase += 0.1 * g.TimeScale()
+}
+
+func (c *Collectible) CollidePlayer(g *Game) {
+	dx, dy := g.PlayerX-c.X, g.PlayerY-c.Y
+	if dx*dx+dy*dy > (PlayerR+c.R)*(PlayerR+c.R) || g.ChangeLevelAnimation {
+		return
+	}
+	g.LevelCollected++
+	g.PlayAt(g.CollectSound, c.X)
+	g.RemoveEntity(c)
+}
+
+func (c *Collectible) Draw(screen *ebiten.Image, g *Game) {
+	y := c.Y + math.Sin(c.phase)*3 // petit flottement
+	if c.Kind == CollectibleCoin {
+		ebitenutil.DrawCircle(screen, c.X, y, c.R, RoleColor(RoleCoin))
+		return
+	}
+	// étoile à 5 branches
+	var path vector.Path
+	for i := 0; i < 10; i++ {
+		r := c.R
+		if i%2 == 1 {
+			r = c.R * 0.45
+		}
+		a := -math.Pi/2 + float64(i)*math.Pi/5
+		px, py := float32(c.X+math.Cos(a)*r), float32(y+math.Sin(a)*r)
+		if i == 0 {
+			path.MoveTo(px, py)
+		} else {
+			path.LineTo(px, py)
+		}
+	}
+	path.Close()
+	op := &vector.DrawPathOptions{AntiAlias: true}
+	op.ColorScale.ScaleWithColor(RoleColor(RoleStar))
+	vector.FillPath(screen, &path, nil, op)
+}
+
+func (c *Collectible) Serialize() EntityData {
+	return serializeEntity("collectible", c)
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Settings sont les préférences du joueur, gardées dans save.json.
+type Settings struct {
+	Fullscreen     bool
+	IntegerScaling bool   // agrandir seulement par multiples entiers (pixels nets)
+	Theme          string // nom de la palette, voir Themes
+	Language       string // langue de l'interface, voir Languages
+	// contrôles changés par le joueur, par nom d'action (voir SetBindings)
+	Controls map[string]Binding `json:",omitempty"`
+	// attente des appuis en ticks, 0 = DefaultInputBuffer, -1 = aucune
+	InputBuffer int `json:",omitempty"`
+}
+
+// Display pose le canvas du jeu (ScreenW x ScreenH) dans la fenêtre, agrandi
+// et centré avec des bandes noires. Le texte du HUD est dessiné ensuite à la
+// résolution réelle de l'écran pour rester net en haute densité.
+type Display struct {
+	IntegerScaling bool
+	Scale          float64 // pixels de l'écran par pixel du canvas
+	OffX, OffY     float64 // coin haut gauche du canvas sur l'écran
+	canvas         *ebiten.Image
+}
+
+// Layout reçoit la taille de la fenêtre en points et renvoie la taille de
+// l'écran en pixels réels.
+func (d *Display) Layout(outsideWidth, outsideHeight float64) (float64, float64) {
+	s := ebiten.Monitor().DeviceScaleFactor()
+	w, h := outsideWidth*s, outsideHeight*s
+	d.Scale = math.Min(w/ScreenW, h/ScreenH)
+	if d.IntegerScaling && d.Scale >= 1 {
+		d.Scale = math.Floor(d.Scale)
+	}
+	d.OffX = (w - ScreenW*d.Scale) / 2
+	d.OffY = (h - ScreenH*d.Scale) / 2
+	return w, h
+}
+
+// Canvas renvoie l'image vide où dessiner le jeu pour cette frame.
+func (d *Display) Canvas() *ebiten.Image {
+	if d.canvas == nil {
+		d.canvas = ebiten.NewImage(ScreenW, ScreenH)
+	}
+	d.canvas.Clear()
+	return d.canvas
+}
+
+// Present dessine le canvas agrandi sur l'écran.
+func (d *Display) Present(screen *ebiten.Image) {
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(d.Scale, d.Scale)
+	op.GeoM.Translate(d.OffX, d.OffY)
+	if d.IntegerScaling {
+		op.Filter = ebiten.FilterNearest
+	} else {
+		op.Filter = ebiten.FilterLinear
+	}
+	screen.DrawImage(d.canvas, op)
+}
+
+// ToCanvas convertit une position de l'écran (souris) en position du canvas.
+func (d *Display) ToCanvas(x, y int) (float64, float64) {
+	if d.Scale == 0 {
+		return float64(x), float64(y)
+	}
+	return (float64(x) - d.OffX) / d.Scale, (float64(y) - d.OffY) / d.Scale
+}
+
+// CursorPosition renvoie la souris dans les coordonnées du canvas.
+func (d *Display) CursorPosition() (float64, float64) {
+	return d.ToCanvas(ebiten.CursorPosition())
+}
+
+// UpdateDisplay gère les touches d'affichage : F11 ou Alt+Entrée pour le
+// plein écran, F10 pour l'agrandissement entier, F9 pour changer de thème,
+// F8 pour changer de langue (aussi dans le menu pause).
+func (g *Game) UpdateDisplay() {
+	alt := ebiten.IsKeyPressed(ebiten.KeyAlt)
+	changed := false
+	if inpututil.IsKeyJustPressed(ebiten.KeyF11) || alt && inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		g.Save.Settings.Fullscreen = !ebiten.IsFullscreen()
+		ebiten.SetFullscreen(g.Save.Settings.Fullscreen)
+		changed = true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF10) {
+		g.Save.Settings.IntegerScaling = !g.Save.Settings.IntegerScaling
+		changed = true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF9) {
+		g.Save.Settings.Theme = NextTheme()
+		changed = true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF8) {
+		g.Save.Settings.Language = NextLanguage()
+		changed = true
+	}
+	g.Display.IntegerScaling = g.Save.Settings.IntegerScaling
+	if changed {
+		SaveToDisk(g.Save, "save.json")
+	}
+}
+
+// Anchor est le point du canvas auquel un élément du HUD est accroché, et le
+// côté du texte aligné sur ce point.
+type Anchor int
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorCenter
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+)
+
+// point renvoie la position de l'ancre dans le canvas et l'alignement du texte.
+func (a Anchor) point() (float64, float64, text.Align, text.Align) {
+	col, row := int(a)%3, int(a)/3
+	aligns := [3]text.Align{text.AlignStart, text.AlignCenter, text.AlignEnd}
+	return float64(col) * ScreenW / 2, float64(row) * ScreenH / 2, aligns[col], aligns[row]
+}
+
+// HUD dessine par dessus le canvas, à la résolution de l'écran. Positions et
+// tailles sont en unités du canvas, relatives à une ancre.
+type HUD struct {
+	screen *ebiten.Image
+	d      *Display
+}
+
+func (d *Display) HUD(screen *ebiten.Image) HUD {
+	return HUD{screen: screen, d: d}
+}
+
+// Pos convertit une position relative à l'ancre en position de l'écran.
+func (h HUD) Pos(anchor Anchor, dx, dy float64) (float64, float64) {
+	ax, ay, _, _ := anchor.point()
+	return h.d.OffX + (ax+dx)*h.d.Scale, h.d.OffY + (ay+dy)*h.d.Scale
+}
+
+// Face renvoie la police du HUD à la taille size (en unités du canvas).
+func (h HUD) Face(size float64) *text.GoTextFace {
+	return &text.GoTextFace{Source: mplusFaceSource, Size: size * h.d.Scale}
+}
+
+// TextWidth renvoie la largeur de s en unités du canvas.
+func (h HUD) TextWidth(s string, size float64) float64 {
+	w, _ := text.Measure(s, &text.GoTextFace{Source: mplusFaceSource, Size: size}, 0)
+	return w
+}
+
+// Text écrit s aligné sur l'ancre, décalé de (dx, dy).
+func (h HUD) Text(s string, size float64, anchor Anchor, dx, dy float64, clr color.Color) {
+	_, _, primary, secondary := anchor.point()
+	x, y := h.Pos(anchor, dx, dy)
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x, y)
+	op.ColorScale.ScaleWithColor(clr)
+	op.PrimaryAlign = primary
+	op.SecondaryAlign = secondary
+	text.Draw(h.screen, s, h.Face(size), op)
+}
+
+func (h HUD) Rect(anchor Anchor, dx, dy, w, ht float64, clr color.Color) {
+	x, y := h.Pos(anchor, dx, dy)
+	vector.DrawFilledRect(h.screen, float32(x), float32(y), float32(w*h.d.Scale), float32(ht*h.d.Scale), clr, false)
+}
+
+func (h HUD) Circle(anchor Anchor, dx, dy, r float64, clr color.Color) {
+	x, y := h.Pos(anchor, dx, dy)
+	vector.DrawFilledCircle(h.screen, float32(x), float32(y), float32(r*h.d.Scale), clr, true)
+}
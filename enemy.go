@@ -0,0 +1,191 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// CircleTouchesPlayer teste le contact entre le joueur et un cercle.
+func CircleTouchesPlayer(g *Game, x, y, r float64) bool {
+	dx, dy := g.PlayerX-x, g.PlayerY-y
+	return dx*dx+dy*dy <= (PlayerR+r)*(PlayerR+r)
+}
+
+// AddEntity ajoute une entité en cours de partie (projectiles...).
+// Elle ne sera mise à jour qu'à partir du tick suivant.
+func (g *Game) AddEntity(e Entity) {
+	g.Entities = append(g.Entities, e)
+}
+
+// Patroller est un ennemi qui suit son chemin. X et Y sont son centre.
+type Patroller struct {
+	X, Y, R float64
+	Path    *Path
+}
+
+func NewPatroller(x, y float64, p *Path) *Patroller {
+	return &Patroller{X: x, Y: y, R: 15, Path: p}
+}
+
+func (p *Patroller) Update(g *Game) {
+	if p.Path != nil {
+		p.X, p.Y = p.Path.Step(p.X, p.Y, g.TimeScale())
+	}
+}
+
+func (p *Patroller) CollidePlayer(g *Game) {
+	if CircleTouchesPlayer(g, p.X, p.Y, p.R) {
+		g.HitHazard()
+	}
+}
+
+func (p *Patroller) SweepRect(g *Game) (float64, float64, float64, float64, bool) {
+	return p.X - p.R, p.Y - p.R, 2 * p.R, 2 * p.R, true
+}
+
+func (p *Patroller) Draw(screen *ebiten.Image, g *Game) {
+	// piques autour du corps
+	for i := 0; i < 8; i++ {
+		a := float64(i) * math.Pi / 4
+		ebitenutil.DrawCircle(screen, p.X+math.Cos(a)*p.R, p.Y+math.Sin(a)*p.R, 4, RoleColor(RolePatroller))
+	}
+	ebitenutil.DrawCircle(screen, p.X, p.Y, p.R, RoleColor(RolePatroller))
+}
+
+func (p *Patroller) Serialize() EntityData {
+	return serializeEntity("patroller", p)
+}
+
+// Chaser fonce vers le joueur en tournant d'au plus MaxTurn radians par tick.
+type Chaser struct {
+	X, Y, R        float64
+	HomeX, HomeY   float64
+	Speed, MaxTurn float64
+	Heading        float64
+}
+
+func NewChaser(x, y, speed, maxTurn float64) *Chaser {
+	return &Chaser{X: x, Y: y, R: 14, HomeX: x, HomeY: y, Speed: speed, MaxTurn: maxTurn, Heading: math.Pi}
+}
+
+func (c *Chaser) Update(g *Game) {
+	dt := g.TimeScale()
+	want := math.Atan2(g.PlayerY-c.Y, g.PlayerX-c.X)
+	// plus petit angle entre le cap et la cible, dans [-Pi, Pi]
+	diff := math.Remainder(want-c.Heading, 2*math.Pi)
+	maxTurn := c.MaxTurn * dt
+	c.Heading += math.Max(-maxTurn, math.Min(maxTurn, diff))
+	c.X += math.Cos(c.Heading) * c.Speed * dt
+	c.Y += math.Sin(c.Heading) * c.Speed * dt
+}
+
+func (c *Chaser) CollidePlayer(g *Game) {
+	if CircleTouchesPlayer(g, c.X, c.Y, c.R) {
+		g.HitHazard()
+		// retour au point de départ pour ne pas attendre le joueur au spawn
+		c.X, c.Y = c.HomeX, c.HomeY
+	}
+}
+
+func (c *Chaser) SweepRect(g *Game) (float64, float64, float64, float64, bool) {
+	return c.X - c.R, c.Y - c.R, 2 * c.R, 2 * c.R, true
+}
+
+func (c *Chaser) Draw(screen *ebiten.Image, g *Game) {
+	ebitenutil.DrawCircle(screen, c.X, c.Y, c.R, RoleColor(RoleChaser))
+	// œil tourné vers le cap
+	ex, ey := c.X+math.Cos(c.Heading)*c.R*0.5, c.Y+math.Sin(c.Heading)*c.R*0.5
+	ebitenutil.DrawCircle(screen, ex, ey, 4, color.White)
+}
+
+func (c *Chaser) Serialize() EntityData {
+	return serializeEntity("chaser", c)
+}
+
+// Turret tire un projectile toutes les Interval ticks, vers le joueur si Aim,
+// sinon dans la direction Angle.
+type Turret struct {
+	X, Y            float64
+	Interval        int
+	ProjectileSpeed float64
+	Aim             bool
+	Angle           float64
+	timer           float64
+}
+
+func NewTurret(x, y float64, interval int, speed float64) *Turret {
+	return &Turret{X: x, Y: y, Interval: interval, ProjectileSpeed: speed, Aim: true}
+}
+
+func (t *Turret) Update(g *Game) {
+	if t.Aim {
+		t.Angle = math.Atan2(g.PlayerY-t.Y, g.PlayerX-t.X)
+	}
+	t.timer += g.TimeScale()
+	if t.timer < float64(t.Interval) {
+		return
+	}
+	t.timer = 0
+	g.AddEntity(&Projectile{
+		X:    t.X + math.Cos(t.Angle)*20,
+		Y:    t.Y + math.Sin(t.Angle)*20,
+		VX:   math.Cos(t.Angle) * t.ProjectileSpeed,
+		VY:   math.Sin(t.Angle) * t.ProjectileSpeed,
+		R:    6,
+		Life: 300,
+	})
+	g.PlayAt(g.barrelShootSound, t.X)
+}
+
+func (t *Turret) CollidePlayer(g *Game) {
+	if CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, t.X-15, t.Y-15, 30, 30) {
+		g.HitHazard()
+	}
+}
+
+func (t *Turret) SweepRect(g *Game) (float64, float64, float64, float64, bool) {
+	return t.X - 15, t.Y - 15, 30, 30, true
+}
+
+func (t *Turret) Draw(screen *ebiten.Image, g *Game) {
+	DrawRotatedRect(screen, t.X, t.Y-4, 24, 8, t.Angle, RoleColor(RoleTurret))
+	ebitenutil.DrawRect(screen, t.X-15, t.Y-15, 30, 30, RoleColor(RoleTurret))
+}
+
+func (t *Turret) Serialize() EntityData {
+	return serializeEntity("turret", t)
+}
+
+// Projectile tiré par une tourelle, disparaît au contact ou hors de l'écran.
+type Projectile struct {
+	X, Y, VX, VY, R float64
+	Life            float64
+}
+
+func (p *Projectile) Update(g *Game) {
+	dt := g.TimeScale()
+	p.X += p.VX * dt
+	p.Y += p.VY * dt
+	p.Life -= dt
+	if p.Life <= 0 || p.X < -p.R || p.X > g.LevelW+p.R || p.Y < -p.R || p.Y > g.LevelH+p.R {
+		g.RemoveEntity(p)
+	}
+}
+
+func (p *Projectile) CollidePlayer(g *Game) {
+	if CircleTouchesPlayer(g, p.X, p.Y, p.R) {
+		g.HitHazard()
+		g.RemoveEntity(p)
+	}
+}
+
+func (p *Projectile) Draw(screen *ebiten.Image, g *Game) {
+	ebitenutil.DrawCircle(screen, p.X, p.Y, p.R, RoleColor(RoleProjectile))
+}
+
+func (p *Projectile) Serialize() EntityData {
+	return serializeEntity("projectile", p)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Entity est un objet du niveau : baril, obstacle, bouncer...
+// Update fait bouger l'objet une fois par tick, CollidePlayer teste lui-même
+// le contact avec le joueur et y réagit.
+type Entity interface {
+	Update(g *Game)
+	CollidePlayer(g *Game)
+	Draw(screen *ebiten.Image, g *Game)
+	Serialize() EntityData
+}
+
+// Solid est implémenté par les entités que le joueur ne doit pas traverser
+// entre deux ticks (voir MovePlayer).
+type Solid interface {
+	SweepRect(g *Game) (x, y, w, h float64, ok bool)
+}
+
+// RotatedSolid est un Solid tourné de angle autour de son centre.
+type RotatedSolid interface {
+	SweepOBB(g *Game) (x, y, w, h, angle float64, ok bool)
+}
+
+// barrelEntity donne accès au baril de base des différents types de barils.
+type barrelEntity interface {
+	barrel() *Barrel
+}
+
+// EntityData est la forme sérialisable d'une entité.
+type EntityData struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+// entityKinds associe chaque Kind à un constructeur vide pour LoadEntity.
+var entityKinds = map[string]func() Entity{
+	"barrel":      func() Entity { return &Barrel{} },
+	"magic":       func() Entity { return &MagicBarrel{} },
+	"fragile":     func() Entity { return &FragileBarrel{} },
+	"teleporter":  func() Entity { return &TeleporterBarrel{} },
+	"checkpoint":  func() Entity { return &CheckpointBarrel{} },
+	"obstacle":    func() Entity { return &Obstacle{} },
+	"bouncer":     func() Entity { return &Bouncer{} },
+	"collectible": func() Entity { return &Collectible{} },
+	"key":         func() Entity { return &Key{} },
+	"switch":      func() Entity { return &Switch{} },
+	"gate":        func() Entity { return &Gate{} },
+	"patroller":   func() Entity { return &Patroller{} },
+	"chaser":      func() Entity { return &Chaser{} },
+	"turret":      func() Entity { return &Turret{} },
+	"projectile":  func() Entity { return &Projectile{} },
+	"field":       func() Entity { return &FieldZone{} },
+}
+
+func serializeEntity(kind string, e Entity) EntityData {
+	data, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("cannot serialize %s: %v", kind, err)
+	}
+	return EntityData{Kind: kind, Data: data}
+}
+
+func LoadEntity(d EntityData) (Entity, error) {
+	newEntity, ok := entityKinds[d.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown entity kind %q", d.Kind)
+	}
+	e := newEntity()
+	if err := json.Unmarshal(d.Data, e); err != nil {
+		return nil, fmt.Errorf("cannot load %s: %w", d.Kind, err)
+	}
+	return e, nil
+}
+
+// LevelData est la forme sérialisable d'un niveau, lue par l'option -level
+// et écrite par -export.
+type LevelData struct {
+	SpawnX, SpawnY float64
+	W, H           float64
+	Entities       []EntityData
+}
+
+// SaveLevel sérialise le niveau en cours.
+func (g *Game) SaveLevel() LevelData {
+	d := LevelData{SpawnX: g.SpawnX, SpawnY: g.SpawnY, W: g.LevelW, H: g.LevelH}
+	for _, e := range g.Entities {
+		d.Entities = append(d.Entities, e.Serialize())
+	}
+	return d
+}
+
+// LoadLevel remplace le niveau en cours par d.
+func (g *Game) LoadLevel(d LevelData) error {
+	entities := make([]Entity, 0, len(d.Entities))
+	for _, ed := range d.Entities {
+		e, err := LoadEntity(ed)
+		if err != nil {
+			return err
+		}
+		entities = append(entities, e)
+	}
+	g.SpawnX, g.SpawnY = d.SpawnX, d.SpawnY
+	g.LevelW, g.LevelH = d.W, d.H
+	// sans taille dans le fichier, le niveau fait la taille de l'écran
+	if g.LevelW <= 0 {
+		g.LevelW = ScreenW
+	}
+	if g.LevelH <= 0 {
+		g.LevelH = ScreenH
+	}
+	g.Entities = entities
+	return nil
+}
+
+func SaveLevelToDisk(d LevelData, filename string) error {
+	bytes, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, bytes, 0644)
+}
+
+// LoadLevelFromDisk lit un niveau et vérifie que toutes ses entités se
+// chargent, pour ne pas échouer en pleine partie.
+func LoadLevelFromDisk(filename string) (LevelData, error) {
+	var d LevelData
+	bytes, err := os.ReadFile(filename)
+	if err != nil {
+		return d, err
+	}
+	if err := json.Unmarshal(bytes, &d); err != nil {
+		return d, fmt.Errorf("%s: %w", filename, err)
+	}
+	if err := new(Game).LoadLevel(d); err != nil {
+		return d, fmt.Errorf("%s: %w", filename, err)
+	}
+	return d, nil
+}
+
+// RemoveEntity retire une entité à la fin du tick.
+func (g *Game) RemoveEntity(e Entity) {
+	g.removedEntities = append(g.removedEntities, e)
+}
+
+func (g *Game) flushRemovedEntities() {
+	for _, e := range g.removedEntities {
+		if b, ok := e.(barrelEntity); ok {
+			if b.barrel() == g.PlayerBarrel {
+				g.PlayerBarrel = nil
+			}
+			if b.barrel() == g.SpawnBarrel {
+				g.SpawnBarrel = nil
+			}
+		}
+		if i := slices.Index(g.Entities, e); i >= 0 {
+			g.Entities = slices.Delete(g.Entities, i, i+1)
+		}
+	}
+	g.removedEntities = g.removedEntities[:0]
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLevelRoundTrip vérifie que chaque niveau, rechargé depuis sa forme
+// sérialisée, se sérialise à l'identique.
+func TestLevelRoundTrip(t *testing.T) {
+	for _, sandbox := range []bool{false, true} {
+		g := &Game{Sandbox: sandbox}
+		for L := 1; L <= g.LevelCount(); L++ {
+			g.Generate_Level(L)
+			want, err := json.Marshal(g.SaveLevel())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var d LevelData
+			if err := json.Unmarshal(want, &d); err != nil {
+				t.Fatal(err)
+			}
+			loaded := &Game{}
+			if err := loaded.LoadLevel(d); err != nil {
+				t.Fatalf("sandbox=%v level %d: %v", sandbox, L, err)
+			}
+			got, err := json.Marshal(loaded.SaveLevel())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("sandbox=%v level %d:\n got %s\nwant %s", sandbox, L, got, want)
+			}
+		}
+	}
+}
+
+// TestPathProgressRoundTrip vérifie qu'un chemin rechargé reprend là où il
+// en était.
+func TestPathProgressRoundTrip(t *testing.T) {
+	p := NewWaypoints(
+		Waypoint{X: 0, Y: 0, Speed: 3},
+		Waypoint{X: 100, Y: 0, Speed: 1, Ease: EaseInOut},
+		Waypoint{X: 100, Y: 80, Speed: 2},
+	)
+	var x, y float64
+	for range 50 {
+		x, y = p.Step(x, y, 1)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var q Path
+	if err := json.Unmarshal(data, &q); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 20 {
+		px, py := p.Step(x, y, 1)
+		qx, qy := q.Step(x, y, 1)
+		if px != qx || py != qy {
+			t.Fatalf("step %d: got (%v, %v), want (%v, %v)", i, qx, qy, px, py)
+		}
+		x, y = px, py
+	}
+}
+
+func TestLoadEntityUnknownKind(t *testing.T) {
+	if _, err := LoadEntity(EntityData{Kind: "dragon", Data: []byte("{}")}); err == nil {
+		t.Fatal("unknown kind loaded")
+	}
+}
+
+func TestLoadLevelDefaultSize(t *testing.T) {
+	var d LevelData
+	if err := json.Unmarshal([]byte(`{"SpawnX": 160, "SpawnY": 240, "Entities": [{"kind": "barrel", "data": {"X": 50, "Y": 215, "W": 100, "H": 50}}]}`), &d); err != nil {
+		t.Fatal(err)
+	}
+	g := &Game{}
+	if err := g.LoadLevel(d); err != nil {
+		t.Fatal(err)
+	}
+	if g.LevelW != ScreenW || g.LevelH != ScreenH {
+		t.Fatalf("level size %vx%v, want %vx%v", g.LevelW, g.LevelH, ScreenW, ScreenH)
+	}
+}
+
+func TestLoadLevelFromDiskRejectsBadEntity(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "level.json")
+	if err := os.WriteFile(name, []byte(`{"Entities": [{"kind": "dragon", "data": {}}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLevelFromDisk(name); err == nil {
+		t.Fatal("level with an unknown entity loaded")
+	}
+}
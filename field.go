@@ -0,0 +1,99 @@
+package main
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// MinFieldSpeed est la vitesse en dessous de laquelle le freinage s'arrête,
+// pour que le joueur ne reste pas bloqué dans une zone.
+const MinFieldSpeed = 2
+
+// FieldZone est une zone rectangulaire qui agit sur la vitesse du joueur
+// lancé tant que son centre est dedans : accélération (AX, AY) en px par
+// tick², gravité ou vent, et freinage Drag en fraction de vitesse par tick.
+type FieldZone struct {
+	X, Y, W, H float64
+	AX, AY     float64
+	Drag       float64
+	Role       ColorRole
+	phase      float64
+}
+
+func NewGravityZone(x, y, w, h, gravity float64) *FieldZone {
+	return &FieldZone{X: x, Y: y, W: w, H: h, AY: gravity, Role: RoleGravityZone}
+}
+
+// NewWindZone pousse le joueur dans la direction angle (0 = vers la droite).
+func NewWindZone(x, y, w, h, angle, strength float64) *FieldZone {
+	return &FieldZone{X: x, Y: y, W: w, H: h, AX: math.Cos(angle) * strength, AY: math.Sin(angle) * strength, Role: RoleWindZone}
+}
+
+func NewDragZone(x, y, w, h, drag float64) *FieldZone {
+	return &FieldZone{X: x, Y: y, W: w, H: h, Drag: drag, Role: RoleDragZone}
+}
+
+func (z *FieldZone) Update(g *Game) {
+	z.phase += g.TimeScale()
+}
+
+func (z *FieldZone) CollidePlayer(g *Game) {
+	if !g.PlayerMoved || !Within(g.PlayerX, g.PlayerY, z.X, z.Y, z.W, z.H) {
+		return
+	}
+	dt := g.TimeScale()
+	g.PlayerVX += z.AX * dt
+	g.PlayerVY += z.AY * dt
+	speed := math.Hypot(g.PlayerVX, g.PlayerVY)
+	if z.Drag > 0 && speed > MinFieldSpeed {
+		g.SetPlayerSpeed(math.Max(MinFieldSpeed, speed*math.Pow(1-z.Drag, dt)))
+		return
+	}
+	g.PlayerSpeed = speed
+}
+
+func (z *FieldZone) Draw(screen *ebiten.Image, g *Game) {
+	tint := RoleColor(z.Role)
+	tint.A = 30
+	ebitenutil.DrawRect(screen, z.X, z.Y, z.W, z.H, tint)
+	streak := RoleColor(z.Role)
+	streak.A = 140
+	// traînées qui défilent dans le sens de la force, points lents pour le freinage
+	force := math.Hypot(z.AX, z.AY)
+	dx, dy := 0.0, 0.0
+	if force > 0 {
+		dx, dy = z.AX/force, z.AY/force
+	}
+	speed := 0.3 + force*6
+	n := int(z.W * z.H / 2500)
+	for i := 0; i < n; i++ {
+		// positions pseudo-aléatoires fixes (suite du nombre d'or)
+		fx := math.Mod(float64(i)*0.618034, 1)
+		fy := math.Mod(float64(i)*0.381966+fx*0.5, 1)
+		x := z.X + wrap(fx*z.W+dx*z.phase*speed, z.W)
+		y := z.Y + wrap(fy*z.H+dy*z.phase*speed, z.H)
+		if force == 0 {
+			vector.DrawFilledCircle(screen, float32(x), float32(y), 2, streak, true)
+			continue
+		}
+		x0, y0 := math.Max(z.X, x-dx*12), math.Max(z.Y, y-dy*12)
+		x0, y0 = math.Min(z.X+z.W, x0), math.Min(z.Y+z.H, y0)
+		vector.StrokeLine(screen, float32(x0), float32(y0), float32(x), float32(y), 1.5, streak, true)
+	}
+}
+
+func (z *FieldZone) Serialize() EntityData {
+	return serializeEntity("field", z)
+}
+
+// wrap ramène v dans [0, w), même quand v est très négatif.
+func wrap(v, w float64) float64 {
+	m := math.Mod(v, w)
+	if m < 0 {
+		m += w
+	}
+	return m
+}
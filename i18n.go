@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Identifiants des messages de l'interface.
+const (
+	MsgLives          = "lives"
+	MsgLevel          = "level"
+	MsgTime           = "time"
+	MsgVersion        = "version"
+	MsgSlowMotion     = "slow_motion"
+	MsgRestart        = "restart"
+	MsgFullCompletion = "full_completion"
+	MsgScoreLine      = "score_line"
+	MsgUserName       = "user_name"
+	MsgEnterCode      = "enter_code"
+	MsgCode           = "code"
+	MsgInvalidCode    = "invalid_code"
+	MsgDownloading    = "downloading"
+	MsgNameTooLong    = "name_too_long"
+	MsgNameNotAlnum   = "name_not_alnum"
+	MsgNameEmpty      = "name_empty"
+	MsgCodeCharset    = "code_charset"
+	MsgCodeTooLong    = "code_too_long"
+	MsgPaused         = "paused"
+	MsgResume         = "resume"
+	MsgControls       = "controls"
+	MsgResetControls  = "reset_controls"
+	MsgBack           = "back"
+	MsgPressKey       = "press_key"
+	MsgActionLaunch   = "action_launch"
+	MsgActionPause    = "action_pause"
+	MsgActionRestart  = "action_restart"
+	MsgActionUp       = "action_up"
+	MsgActionDown     = "action_down"
+	MsgActionLeft     = "action_left"
+	MsgActionRight    = "action_right"
+	MsgActionConfirm  = "action_confirm"
+	MsgActionBack     = "action_back"
+	MsgInputBuffer    = "input_buffer"
+	MsgTicks          = "ticks"
+	MsgOff            = "off"
+	MsgLanguage       = "language"
+	MsgLanguageName   = "language_name" // nom de la langue dans cette langue
+)
+
+// Message est un texte traduit. One est la forme du singulier, utilisée par
+// TN selon la règle de pluriel de la langue ; les messages sans nombre n'ont
+// que Other.
+type Message struct {
+	One, Other string
+}
+
+// Languages liste les langues proposées, la première sert par défaut.
+var Languages = []string{"en", "fr"}
+
+var catalogs = map[string]map[string]Message{
+	"en": {
+		MsgLives:          {One: "Life: %d", Other: "Lives: %d"},
+		MsgLevel:          {Other: "Level %d"},
+		MsgTime:           {Other: "Time: %s"},
+		MsgVersion:        {Other: "version %s"},
+		MsgSlowMotion:     {Other: "Slow Motion!"},
+		MsgRestart:        {Other: "Restart"},
+		MsgFullCompletion: {Other: "100%"},
+		MsgScoreLine:      {Other: "%d. %v. - %s"},
+		MsgUserName:       {Other: "UserName:"},
+		MsgEnterCode:      {Other: "Enter a code"},
+		MsgCode:           {Other: "Code:"},
+		MsgInvalidCode:    {Other: "Invalid code"},
+		MsgDownloading:    {Other: "Downloading UserName..."},
+		MsgNameTooLong:    {Other: "Username is too long."},
+		MsgNameNotAlnum:   {Other: "Username must be alphanumeric."},
+		MsgNameEmpty:      {Other: "Username cannot be empty."},
+		MsgCodeCharset:    {Other: "Spaces are not allowed in codes."},
+		MsgCodeTooLong:    {Other: "Code is too long."},
+		MsgPaused:         {Other: "Paused"},
+		MsgResume:         {Other: "Resume"},
+		MsgControls:       {Other: "Controls"},
+		MsgResetControls:  {Other: "Reset controls"},
+		MsgBack:           {Other: "Back"},
+		MsgPressKey:       {Other: "Press a key..."},
+		MsgActionLaunch:   {Other: "Launch"},
+		MsgActionPause:    {Other: "Pause"},
+		MsgActionRestart:  {Other: "Restart"},
+		MsgActionUp:       {Other: "Up"},
+		MsgActionDown:     {Other: "Down"},
+		MsgActionLeft:     {Other: "Left"},
+		MsgActionRight:    {Other: "Right"},
+		MsgActionConfirm:  {Other: "Confirm"},
+		MsgActionBack:     {Other: "Back"},
+		MsgInputBuffer:    {Other: "Input buffer"},
+		MsgTicks:          {One: "%d frame", Other: "%d frames"},
+		MsgOff:            {Other: "Off"},
+		MsgLanguage:       {Other: "Language"},
+		MsgLanguageName:   {Other: "English"},
+	},
+	"fr": {
+		MsgLives:          {One: "Vie : %d", Other: "Vies : %d"},
+		MsgLevel:          {Other: "Niveau %d"},
+		MsgTime:           {Other: "Temps : %s"},
+		MsgVersion:        {Other: "version %s"},
+		MsgSlowMotion:     {Other: "Ralenti !"},
+		MsgRestart:        {Other: "Rejouer"},
+		MsgFullCompletion: {Other: "100 %"},
+		MsgScoreLine:      {Other: "%d. %v. - %s"},
+		MsgUserName:       {Other: "Pseudo :"},
+		MsgEnterCode:      {Other: "Entrez un code"},
+		MsgCode:           {Other: "Code :"},
+		MsgInvalidCode:    {Other: "Code invalide"},
+		MsgDownloading:    {Other: "Chargement du pseudo..."},
+		MsgNameTooLong:    {Other: "Le pseudo est trop long."},
+		MsgNameNotAlnum:   {Other: "Lettres et chiffres seulement."},
+		MsgNameEmpty:      {Other: "Le pseudo est vide."},
+		MsgCodeCharset:    {Other: "Pas d'espace dans le code."},
+		MsgCodeTooLong:    {Other: "Le code est trop long."},
+		MsgPaused:         {Other: "Pause"},
+		MsgResume:         {Other: "Reprendre"},
+		MsgControls:       {Other: "Contrôles"},
+		MsgResetControls:  {Other: "Par défaut"},
+		MsgBack:           {Other: "Retour"},
+		MsgPressKey:       {Other: "Appuyez..."},
+		MsgActionLaunch:   {Other: "Tirer"},
+		MsgActionPause:    {Other: "Pause"},
+		MsgActionRestart:  {Other: "Rejouer"},
+		MsgActionUp:       {Other: "Haut"},
+		MsgActionDown:     {Other: "Bas"},
+		MsgActionLeft:     {Other: "Gauche"},
+		MsgActionRight:    {Other: "Droite"},
+		MsgActionConfirm:  {Other: "Valider"},
+		MsgActionBack:     {Other: "Retour"},
+		MsgInputBuffer:    {Other: "Mémoire des appuis"},
+		MsgTicks:          {One: "%d image", Other: "%d images"},
+		MsgOff:            {Other: "Non"},
+		MsgLanguage:       {Other: "Langue"},
+		MsgLanguageName:   {Other: "Français"},
+	},
+}
+
+// pluralOne indique si n prend la forme du singulier dans la langue.
+var pluralOne = map[string]func(n int) bool{
+	"en": func(n int) bool { return n == 1 },
+	"fr": func(n int) bool { return n == 0 || n == 1 }, // « 0 vie »
+}
+
+var lang = Languages[0]
+
+// SetLanguage choisit la langue de l'interface, la langue par défaut si elle
+// n'est pas traduite.
+func SetLanguage(l string) {
+	lang = Languages[0]
+	if slices.Contains(Languages, l) {
+		lang = l
+	}
+}
+
+// NextLanguage passe à la langue suivante et la renvoie.
+func NextLanguage() string {
+	i := slices.Index(Languages, lang)
+	lang = Languages[(i+1)%len(Languages)]
+	return lang
+}
+
+func lookup(id string) Message {
+	if m, ok := catalogs[lang][id]; ok {
+		return m
+	}
+	log.Printf("missing %s message %q", lang, id)
+	return catalogs[Languages[0]][id]
+}
+
+// T renvoie le message id traduit, formaté avec args.
+func T(id string, args ...any) string {
+	m := lookup(id)
+	if len(args) == 0 {
+		return m.Other
+	}
+	return fmt.Sprintf(m.Other, args...)
+}
+
+// TN renvoie le message id au singulier ou au pluriel selon n.
+func TN(id string, n int, args ...any) string {
+	m := lookup(id)
+	format := m.Other
+	if m.One != "" && pluralOne[lang](n) {
+		format = m.One
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// CheckCatalogs vérifie que chaque langue traduit tous les messages de la
+// langue par défaut, avec les mêmes formes de pluriel et les mêmes verbes
+// de formatage.
+func CheckCatalogs() error {
+	ref := catalogs[Languages[0]]
+	var problems []string
+	for _, l := range Languages {
+		c, ok := catalogs[l]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("no catalog for %q", l))
+			continue
+		}
+		if pluralOne[l] == nil {
+			problems = append(problems, fmt.Sprintf("no plural rule for %q", l))
+		}
+		for id, want := range ref {
+			got, ok := c[id]
+			switch {
+			case !ok || got.Other == "":
+				problems = append(problems, fmt.Sprintf("%s: missing %q", l, id))
+			case (want.One == "") != (got.One == ""):
+				problems = append(problems, fmt.Sprintf("%s: %q plural forms differ", l, id))
+			case verbs(got.Other) != verbs(want.Other):
+				problems = append(problems, fmt.Sprintf("%s: %q format %q does not match %q", l, id, got.Other, want.Other))
+			}
+		}
+		for id := range c {
+			if _, ok := ref[id]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown message %q", l, id))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("message catalogs: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// verbs renvoie les verbes de formatage de s dans l'ordre (%d, %s...).
+func verbs(s string) string {
+	var out []byte
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] == '%' {
+			i++
+			if s[i] != '%' && s[i] != ' ' {
+				out = append(out, s[i])
+			}
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCatalogs(t *testing.T) {
+	if err := CheckCatalogs(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestMessagesUsedAreTranslated vérifie que chaque identifiant Msg* utilisé
+// dans le code existe dans toutes les langues.
+func TestMessagesUsedAreTranslated(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	ids := map[string]string{} // constante -> identifiant du message
+	used := map[string]bool{}
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ValueSpec:
+				for i, name := range n.Names {
+					if !strings.HasPrefix(name.Name, "Msg") || i >= len(n.Values) {
+						continue
+					}
+					if lit, ok := n.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						ids[name.Name], _ = strconv.Unquote(lit.Value)
+					}
+				}
+			case *ast.Ident:
+				if strings.HasPrefix(n.Name, "Msg") {
+					used[n.Name] = true
+				}
+			}
+			return true
+		})
+	}
+	if len(ids) == 0 {
+		t.Fatal("no Msg* constants found")
+	}
+	for name := range used {
+		id, ok := ids[name]
+		if !ok {
+			t.Errorf("%s is not a message constant", name)
+			continue
+		}
+		for _, l := range Languages {
+			if m, ok := catalogs[l][id]; !ok || m.Other == "" {
+				t.Errorf("%s: missing %s (%q)", l, name, id)
+			}
+		}
+	}
+}
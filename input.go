@@ -0,0 +1,316 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Action est une commande du jeu. Chaque action est liée à des touches du
+// clavier et à des boutons de manette, modifiables depuis l'écran des contrôles.
+type Action int
+
+const (
+	ActionLaunch Action = iota
+	ActionPause
+	ActionRestart
+	ActionUp
+	ActionDown
+	ActionLeft
+	ActionRight
+	ActionConfirm
+	ActionBack
+	ActionCount
+)
+
+// actionNames sert de clé dans save.json.
+var actionNames = [ActionCount]string{"launch", "pause", "restart", "up", "down", "left", "right", "confirm", "back"}
+
+func (a Action) String() string {
+	return actionNames[a]
+}
+
+// Binding liste les touches et les boutons (disposition standard) d'une action.
+type Binding struct {
+	Keys    []ebiten.Key                   `json:",omitempty"`
+	Buttons []ebiten.StandardGamepadButton `json:",omitempty"`
+}
+
+var defaultBindings = [ActionCount]Binding{
+	ActionLaunch:  {Keys: []ebiten.Key{ebiten.KeySpace}, Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonRightBottom}},
+	ActionPause:   {Keys: []ebiten.Key{ebiten.KeyEscape, ebiten.KeyP}, Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonCenterRight}},
+	ActionRestart: {Keys: []ebiten.Key{ebiten.KeyR}, Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonRightTop}},
+	ActionUp:      {Keys: []ebiten.Key{ebiten.KeyArrowUp, ebiten.KeyW}, Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftTop}},
+	ActionDown:    {Keys: []ebiten.Key{ebiten.KeyArrowDown, ebiten.KeyS}, Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftBottom}},
+	ActionLeft:    {Keys: []ebiten.Key{ebiten.KeyArrowLeft}, Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftLeft}},
+	ActionRight:   {Keys: []ebiten.Key{ebiten.KeyArrowRight}, Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftRight}},
+	ActionConfirm: {Keys: []ebiten.Key{ebiten.KeyEnter}, Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonRightBottom}},
+	ActionBack:    {Keys: []ebiten.Key{ebiten.KeyEscape, ebiten.KeyBackspace}, Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonRightRight}},
+}
+
+var bindings = defaultBindings
+
+// SetBindings applique les contrôles enregistrés par dessus ceux par défaut.
+func SetBindings(saved map[string]Binding) {
+	for a := range ActionCount {
+		bindings[a] = defaultBindings[a]
+		if b, ok := saved[a.String()]; ok {
+			bindings[a] = b
+		}
+	}
+}
+
+// ResetBindings remet les contrôles par défaut.
+func ResetBindings() {
+	bindings = defaultBindings
+}
+
+// SavedBindings renvoie les contrôles courants pour les Settings.
+func SavedBindings() map[string]Binding {
+	saved := map[string]Binding{}
+	for a := range ActionCount {
+		saved[a.String()] = bindings[a]
+	}
+	return saved
+}
+
+// BindKey remplace les touches de l'action par key. Une autre action qui
+// utilisait key prend à la place les anciennes touches de a.
+func BindKey(a Action, key ebiten.Key) {
+	bind(a, key, func(b *Binding) *[]ebiten.Key { return &b.Keys })
+}
+
+// BindButton remplace les boutons de manette de l'action par button, en
+// échangeant comme BindKey.
+func BindButton(a Action, button ebiten.StandardGamepadButton) {
+	bind(a, button, func(b *Binding) *[]ebiten.StandardGamepadButton { return &b.Buttons })
+}
+
+// sharedActions sont les actions qui partagent une touche par défaut sans se
+// gêner (Échap pour la pause et le retour, A pour tirer et valider).
+var sharedActions = [][2]Action{{ActionPause, ActionBack}, {ActionLaunch, ActionConfirm}}
+
+// CanShare indique si a et b peuvent être liées à la même touche.
+func CanShare(a, b Action) bool {
+	return slices.Contains(sharedActions, [2]Action{a, b}) || slices.Contains(sharedActions, [2]Action{b, a})
+}
+
+func bind[T comparable](a Action, v T, list func(*Binding) *[]T) {
+	old := slices.DeleteFunc(slices.Clone(*list(&bindings[a])), func(x T) bool { return x == v })
+	for o := range ActionCount {
+		l := list(&bindings[o])
+		if o == a || CanShare(a, o) || !slices.Contains(*l, v) {
+			continue
+		}
+		*l = slices.DeleteFunc(slices.Clone(*l), func(x T) bool { return x == v })
+		for _, x := range old {
+			if !slices.Contains(*l, x) {
+				*l = append(*l, x)
+			}
+		}
+	}
+	*list(&bindings[a]) = []T{v}
+}
+
+var gamepadIDs []ebiten.GamepadID
+
+// Gamepads renvoie les manettes branchées qui ont la disposition standard.
+func Gamepads() []ebiten.GamepadID {
+	gamepadIDs = ebiten.AppendGamepadIDs(gamepadIDs[:0])
+	return slices.DeleteFunc(gamepadIDs, func(id ebiten.GamepadID) bool {
+		return !ebiten.IsStandardGamepadLayoutAvailable(id)
+	})
+}
+
+// DefaultInputBuffer est la fenêtre par défaut, en ticks, pendant laquelle
+// un appui reste en attente (voir Input).
+const DefaultInputBuffer = 6
+
+// Input garde l'état des actions d'un tick à l'autre : une action est
+// « juste appuyée » au premier tick où l'une de ses touches ou l'un de ses
+// boutons est enfoncé, quel que soit l'appareil. Chaque appui reste aussi en
+// attente pendant Buffer ticks, pour qu'un tir demandé un peu avant
+// l'atterrissage parte à l'atterrissage (voir ConsumeAction). Les touchers
+// de l'écran tactile comptent aussi, voir touch.go.
+type Input struct {
+	Buffer   int
+	down     [ActionCount]bool
+	prev     [ActionCount]bool
+	buffered [ActionCount]int  // ticks d'attente restants
+	pad      [ActionCount]bool // tenue seulement par une manette
+	PadUsed  bool              // une manette a déjà servi : on affiche le clavier à l'écran
+
+	Buttons   []TouchButton // boutons à l'écran pour ce tick
+	TouchUsed bool          // l'écran a déjà été touché : on affiche les commandes tactiles
+	touches   map[ebiten.TouchID]Touch
+	next      map[ebiten.TouchID]Touch
+	started   []Touch
+	touchIDs  []ebiten.TouchID
+	points    []TouchPoint
+	simulated map[ebiten.TouchID][2]float64
+}
+
+var input = Input{Buffer: DefaultInputBuffer}
+
+// BufferOptions sont les fenêtres proposées par l'écran des contrôles,
+// -1 pour aucune.
+var BufferOptions = []int{-1, 3, DefaultInputBuffer, 10}
+
+// SetInputBuffer applique la fenêtre des Settings : 0 pour celle par défaut,
+// négative pour aucune.
+func SetInputBuffer(ticks int) {
+	switch {
+	case ticks < 0:
+		input.Buffer = 0
+	case ticks == 0:
+		input.Buffer = DefaultInputBuffer
+	default:
+		input.Buffer = ticks
+	}
+}
+
+// Update lit le clavier, les manettes et l'écran tactile, une fois au début
+// de chaque tick. toCanvas convertit une position de l'écran en position du
+// canvas.
+func (in *Input) Update(toCanvas func(x, y int) (float64, float64)) {
+	var pressed [ActionCount]DevicePress
+	pads := Gamepads()
+	for a := range ActionCount {
+		pressed[a] = DevicePress{Keys: keysPressed(bindings[a]), Buttons: buttonsPressed(bindings[a], pads)}
+	}
+	in.update(pressed, in.pollTouches(toCanvas))
+}
+
+// DevicePress dit si une action est tenue au clavier et à la manette.
+type DevicePress struct {
+	Keys, Buttons bool
+}
+
+// update avance d'un tick à partir des appareils déjà relevés, ce qui permet
+// de simuler des appuis et des doigts.
+func (in *Input) update(pressed [ActionCount]DevicePress, points []TouchPoint) {
+	in.prev = in.down
+	in.updateTouches(points)
+	for a := range ActionCount {
+		p := pressed[a]
+		in.down[a] = p.Keys || p.Buttons || in.touching(a)
+		in.pad[a] = p.Buttons && !p.Keys
+		if in.pad[a] && !in.prev[a] {
+			in.PadUsed = true
+		}
+		if in.buffered[a] > 0 {
+			in.buffered[a]--
+		}
+		if in.down[a] && !in.prev[a] {
+			// sans attente, l'appui compte quand même pour ce tick
+			in.buffered[a] = max(in.Buffer, 1)
+		}
+	}
+}
+
+// Hold marque l'action comme déjà tenue : la touche qu'on vient de lier ne
+// compte comme un appui qu'après avoir été relâchée.
+func (in *Input) Hold(a Action) {
+	in.down[a], in.prev[a] = true, true
+	in.buffered[a] = 0
+}
+
+// Reset oublie les appuis en attente, pour qu'un appui qui a servi dans un
+// menu ne tire pas en reprenant la partie.
+func (in *Input) Reset() {
+	clear(in.buffered[:])
+}
+
+func keysPressed(b Binding) bool {
+	for _, k := range b.Keys {
+		// Alt+Entrée passe en plein écran, ce n'est pas une validation
+		if k == ebiten.KeyEnter && ebiten.IsKeyPressed(ebiten.KeyAlt) {
+			continue
+		}
+		if ebiten.IsKeyPressed(k) {
+			return true
+		}
+	}
+	return false
+}
+
+func buttonsPressed(b Binding, pads []ebiten.GamepadID) bool {
+	for _, id := range pads {
+		for _, btn := range b.Buttons {
+			if ebiten.IsStandardGamepadButtonPressed(id, btn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsActionPressed indique si une touche ou un bouton de l'action est tenu.
+func IsActionPressed(a Action) bool {
+	return input.down[a]
+}
+
+// IsActionJustPressed indique si l'action vient d'être enfoncée ce tick.
+func IsActionJustPressed(a Action) bool {
+	return input.down[a] && !input.prev[a]
+}
+
+// IsActionJustReleased indique si l'action vient d'être relâchée ce tick.
+func IsActionJustReleased(a Action) bool {
+	return !input.down[a] && input.prev[a]
+}
+
+// IsActionFromPad indique si l'action est tenue à la manette et pas au
+// clavier : sur l'écran du pseudo, Confirm tape alors la touche choisie du
+// clavier à l'écran au lieu de valider.
+func IsActionFromPad(a Action) bool {
+	return input.pad[a]
+}
+
+// ConsumeAction renvoie true si l'action a été enfoncée il y a moins de
+// Buffer ticks, et retire cet appui de l'attente. À n'appeler que quand
+// l'action peut servir, sinon l'appui est perdu.
+func ConsumeAction(a Action) bool {
+	if input.buffered[a] == 0 {
+		return false
+	}
+	input.buffered[a] = 0
+	return true
+}
+
+// buttonNames donne le nom court des boutons de la disposition standard
+// (noms de la manette Xbox).
+var buttonNames = [...]string{
+	ebiten.StandardGamepadButtonRightBottom:      "A",
+	ebiten.StandardGamepadButtonRightRight:       "B",
+	ebiten.StandardGamepadButtonRightLeft:        "X",
+	ebiten.StandardGamepadButtonRightTop:         "Y",
+	ebiten.StandardGamepadButtonFrontTopLeft:     "LB",
+	ebiten.StandardGamepadButtonFrontTopRight:    "RB",
+	ebiten.StandardGamepadButtonFrontBottomLeft:  "LT",
+	ebiten.StandardGamepadButtonFrontBottomRight: "RT",
+	ebiten.StandardGamepadButtonCenterLeft:       "Back",
+	ebiten.StandardGamepadButtonCenterRight:      "Start",
+	ebiten.StandardGamepadButtonLeftStick:        "L3",
+	ebiten.StandardGamepadButtonRightStick:       "R3",
+	ebiten.StandardGamepadButtonLeftTop:          "Up",
+	ebiten.StandardGamepadButtonLeftBottom:       "Down",
+	ebiten.StandardGamepadButtonLeftLeft:         "Left",
+	ebiten.StandardGamepadButtonLeftRight:        "Right",
+	ebiten.StandardGamepadButtonCenterCenter:     "Home",
+}
+
+// BindingLabel décrit les touches puis les boutons de l'action, ex. "Space, A".
+func BindingLabel(a Action) string {
+	var names []string
+	for _, k := range bindings[a].Keys {
+		names = append(names, k.String())
+	}
+	for _, btn := range bindings[a].Buttons {
+		if int(btn) < len(buttonNames) {
+			names = append(names, buttonNames[btn])
+		}
+	}
+	return strings.Join(names, ", ")
+}
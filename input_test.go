@@ -0,0 +1,50 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// conflicts renvoie les paires d'actions liées à une même touche ou un même
+// bouton alors qu'elles ne peuvent pas la partager.
+func conflicts() [][2]Action {
+	var found [][2]Action
+	for a := range ActionCount {
+		for b := a + 1; b < ActionCount; b++ {
+			if CanShare(a, b) {
+				continue
+			}
+			keys := slices.ContainsFunc(bindings[a].Keys, func(k ebiten.Key) bool { return slices.Contains(bindings[b].Keys, k) })
+			buttons := slices.ContainsFunc(bindings[a].Buttons, func(btn ebiten.StandardGamepadButton) bool { return slices.Contains(bindings[b].Buttons, btn) })
+			if keys || buttons {
+				found = append(found, [2]Action{a, b})
+			}
+		}
+	}
+	return found
+}
+
+func TestBindSwapsConflicts(t *testing.T) {
+	defer ResetBindings()
+	ResetBindings()
+	if c := conflicts(); len(c) > 0 {
+		t.Fatalf("default bindings conflict: %v", c)
+	}
+	BindKey(ActionLaunch, ebiten.KeyEscape)
+	BindKey(ActionRestart, ebiten.KeyEnter)
+	BindButton(ActionBack, ebiten.StandardGamepadButtonCenterRight)
+	if c := conflicts(); len(c) > 0 {
+		t.Fatalf("conflicting bindings: %v", c)
+	}
+	// la pause et le retour gardent de quoi quitter le menu
+	for _, a := range []Action{ActionPause, ActionBack, ActionConfirm} {
+		if len(bindings[a].Keys) == 0 {
+			t.Errorf("%v has no key left", a)
+		}
+	}
+	if !slices.Contains(bindings[ActionPause].Keys, ebiten.KeySpace) {
+		t.Errorf("pause keys %v, want the old launch key", bindings[ActionPause].Keys)
+	}
+}
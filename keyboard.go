@@ -0,0 +1,162 @@
+package main
+
+import (
+	"image/color"
+	"unicode"
+)
+
+// Touches spéciales du clavier à l'écran.
+const (
+	kbBackspace = '\b'
+	kbShift     = '\x0e'
+	kbEnter     = '\n'
+)
+
+const (
+	KeyboardKeyW   = 56
+	KeyboardKeyH   = 42
+	KeyboardKeyGap = 6
+)
+
+// keyboardRows est la disposition du clavier, une rune par touche.
+var keyboardRows = []string{
+	"1234567890",
+	"qwertyuiop",
+	"asdfghjkl\b",
+	"\x0ezxcvbnm\n",
+}
+
+// Keyboard est un clavier à l'écran pour saisir le pseudo et le code au
+// doigt ou à la manette. Il s'affiche dès que l'écran tactile ou une manette
+// a servi ; à la manette, les directions déplacent la touche choisie,
+// Confirm la tape et Back efface.
+type Keyboard struct {
+	upper    bool
+	row, col int // touche choisie à la manette
+}
+
+type keyboardKey struct {
+	r          rune
+	row, col   int
+	x, y, w, h float64
+}
+
+// Visible indique si le clavier est affiché.
+func (kb *Keyboard) Visible() bool {
+	return input.TouchUsed || input.PadUsed
+}
+
+// keys renvoie les touches et leur rectangle dans le canvas, en bas de
+// l'écran. Entrée prend la place de deux touches.
+func (kb *Keyboard) keys() []keyboardKey {
+	var keys []keyboardKey
+	y := ScreenH - HUDMargin - float64(len(keyboardRows))*(KeyboardKeyH+KeyboardKeyGap) + KeyboardKeyGap
+	for i, row := range keyboardRows {
+		x := (ScreenW - 10*(KeyboardKeyW+KeyboardKeyGap) + KeyboardKeyGap) / 2.0
+		for j, r := range []rune(row) {
+			w := float64(KeyboardKeyW)
+			if r == kbEnter {
+				w = 2*KeyboardKeyW + KeyboardKeyGap
+			}
+			keys = append(keys, keyboardKey{r: r, row: i, col: j, x: x, y: y, w: w, h: KeyboardKeyH})
+			x += w + KeyboardKeyGap
+		}
+		y += KeyboardKeyH + KeyboardKeyGap
+	}
+	return keys
+}
+
+func (kb *Keyboard) label(r rune) string {
+	switch r {
+	case kbBackspace:
+		return "←"
+	case kbShift:
+		return "Aa"
+	case kbEnter:
+		return "OK"
+	}
+	if kb.upper {
+		r = unicode.ToUpper(r)
+	}
+	return string(r)
+}
+
+// rowLen renvoie le nombre de touches de la rangée i.
+func rowLen(i int) int {
+	return len([]rune(keyboardRows[i]))
+}
+
+// navigate déplace la touche choisie avec les directions de la manette. Au
+// clavier, les flèches restent au curseur du champ.
+func (kb *Keyboard) navigate() {
+	n := len(keyboardRows)
+	switch {
+	case IsActionJustPressed(ActionUp) && IsActionFromPad(ActionUp):
+		kb.row = (kb.row + n - 1) % n
+	case IsActionJustPressed(ActionDown) && IsActionFromPad(ActionDown):
+		kb.row = (kb.row + 1) % n
+	case IsActionJustPressed(ActionLeft) && IsActionFromPad(ActionLeft):
+		kb.col = (kb.col + rowLen(kb.row) - 1) % rowLen(kb.row)
+	case IsActionJustPressed(ActionRight) && IsActionFromPad(ActionRight):
+		kb.col = (kb.col + 1) % rowLen(kb.row)
+	}
+	kb.col = min(kb.col, rowLen(kb.row)-1)
+}
+
+// press tape la touche k dans f et renvoie true si elle valide un texte correct.
+func (kb *Keyboard) press(k keyboardKey, f *TextField) bool {
+	switch k.r {
+	case kbBackspace:
+		f.Backspace()
+	case kbShift:
+		kb.upper = !kb.upper
+	case kbEnter:
+		return f.Submit()
+	default:
+		if kb.upper {
+			f.Type(unicode.ToUpper(k.r))
+		} else {
+			f.Type(k.r)
+		}
+	}
+	return false
+}
+
+// Update tape dans f les touches touchées au doigt ou validées à la manette,
+// et renvoie true quand le joueur valide un texte correct avec OK.
+func (kb *Keyboard) Update(f *TextField) bool {
+	if !kb.Visible() {
+		return false
+	}
+	kb.navigate()
+	if IsActionJustPressed(ActionBack) && IsActionFromPad(ActionBack) {
+		f.Backspace()
+	}
+	confirm := IsActionJustPressed(ActionConfirm) && IsActionFromPad(ActionConfirm)
+	for _, k := range kb.keys() {
+		if input.Tapped(k.x, k.y, k.w, k.h) || confirm && k.row == kb.row && k.col == kb.col {
+			if kb.press(k, f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (kb *Keyboard) Draw(h HUD) {
+	if !kb.Visible() {
+		return
+	}
+	for _, k := range kb.keys() {
+		clr := color.RGBA{60, 60, 60, 230}
+		if k.r == kbEnter || k.r == kbShift && kb.upper {
+			clr = color.RGBA{0, 160, 0, 255}
+		}
+		if input.PadUsed && k.row == kb.row && k.col == kb.col {
+			// cadre de la touche choisie à la manette
+			h.Rect(AnchorTopLeft, k.x-3, k.y-3, k.w+6, k.h+6, color.White)
+		}
+		h.Rect(AnchorTopLeft, k.x, k.y, k.w, k.h, clr)
+		h.Text(kb.label(k.r), 20, AnchorCenter, k.x+k.w/2-ScreenW/2, k.y+k.h/2-ScreenH/2, color.White)
+	}
+}
@@ -3,17 +3,16 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"image/color"
 	"log"
 	"math"
-	"math/rand"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 	"time"
-	"unicode"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
@@ -22,11 +21,18 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/examples/resources/fonts"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/hajimehoshi/go-mp3"
 )
 
 const (
-	PlayerR = 30
+	PlayerR     = 30
+	LaunchSpeed = 15
+	LastLevel   = 7
+	GameVersion = "1.4"
+
+	// SlowMotionFactor ralentit tout ce qui bouge pendant le slow motion.
+	SlowMotionFactor = 0.35
 )
 
 type Score struct {
@@ -37,52 +43,17 @@ type Score struct {
 
 type SaveData struct {
 	Top5 []Score
-}
-type Particle struct {
-	x, y   float64
-	vx, vy float64
-	life   int
-	color  color.Color
-}
-type BarrelsS struct {
-	x           float64
-	y           float64
-	w           float64
-	h           float64
-	BarrelsDirY bool
-	Moved       bool
-	fragile     bool
-	Teleporter  bool
-	Magic       bool
-	TeleporterX float64
-	TeleporterY float64
-	CoolDown    int
-	color       color.Color
-}
-
-type BouncersS struct {
-	x     float64
-	y     float64
-	w     float64
-	h     float64
-	color color.Color
-}
-
-type ObstaclesS struct {
-	x             float64
-	y             float64
-	w             float64
-	h             float64
-	Moved         bool
-	ObstaclesDirY bool
-	color         color.Color
+	// Top5Full classe les parties finies avec tous les objets ramassés.
+	Top5Full []Score `json:",omitempty"`
+	// Collected garde, par joueur puis par niveau, le meilleur nombre d'objets ramassés.
+	Collected map[string]map[int]int `json:",omitempty"`
+	Settings  Settings
 }
 type Game struct {
 	Level                 int
-	Particles             []Particle
-	Barrels               []BarrelsS
-	Obstacles             []ObstaclesS
-	Bouncers              []BouncersS
+	Particles             ParticlePool
+	Entities              []Entity
+	removedEntities       []Entity
 	Top5Bestplayers       []Score
 	Save                  SaveData
 	StartTime             time.Time
@@ -114,21 +85,51 @@ type Game struct {
 	ChangeLevelAnimation  bool
 	EndOfRun              bool
 	TimeSaveAnimation     float64
-	ValidUserName         int
-	TUNE                  float64
 	CurrentCode           string
 	InvalidCode           bool
 	TSICM                 float64
 	TimeBeforeLevelDown   int
 	SpaceCNT              int
-	EnterCNT              int
 	PlayerX               float64
 	PlayerY               float64
 	PlayerSpeed           float64
+	PlayerVX              float64
+	PlayerVY              float64
+	PlayerLeftBarrel      bool
+	AutoFireTimer         float64
 	currentUserName       string
-	PlayerBarrel          int
+	NameField             *TextField
+	CodeField             *TextField
+	PlayerBarrel          *Barrel
 	PlayerLife            int
 	PlayerMoved           bool
+	SpawnX                float64
+	SpawnY                float64
+	SpawnBarrel           *Barrel
+	WarpTimer             float64
+	AnimTick              float64 // temps des animations de sprites
+	Camera                Camera
+	LevelW                float64 // taille du niveau, qui peut dépasser l'écran
+	LevelH                float64
+	Display               Display
+	Keyboard              Keyboard
+	MouseAsTouch          bool       // option -touch : la souris joue le rôle d'un doigt
+	Sandbox               bool       // option -sandbox : niveaux de démonstration, sans classement
+	LevelFile             *LevelData // option -level : seul niveau du bac à sable
+	Paused                bool
+	pausedAt              time.Time
+	PauseMenu             Menu
+	ControlsOpen          bool
+	ControlsMenu          Menu
+	rebinding             bool // l'écran des contrôles attend une touche
+	keys                  []ebiten.Key
+	buttons               []ebiten.StandardGamepadButton
+	CollectSound          *audio.Player
+	LevelCollected        int
+	LevelCollectibles     int
+	RunMissedCollectible  bool
+	Keys                  map[string]int
+	Signals               map[string]bool
 }
 
 var (
@@ -137,6 +138,7 @@ var (
 	backgroundY     float64
 	backgroundW     float64
 	backgroundH     float64
+	whitePixel      *ebiten.Image
 )
 var audioContext = audio.NewContext(44100)
 
@@ -155,25 +157,84 @@ func CircleRectCollision(cx, cy, cr, rx, ry, rw, rh float64) bool {
 
 	return dx*dx+dy*dy <= cr*cr
 }
-func (g *Game) SpawnBarrelExplosion(x, y float64) {
-	for i := 0; i < 20; i++ {
-		g.Particles = append(g.Particles, Particle{
-			x:     x + 50, // milieu du baril
-			y:     y + 25,
-			vx:    (rand.Float64()*4 - 2), // -2 à +2
-			vy:    (rand.Float64()*4 - 2),
-			life:  25 + rand.Intn(10),
-			color: color.RGBA{169, 99, 29, 255}, // brun clair éclats bois
-		})
-	}
+
+// CircleOBBCollision teste un cercle contre un rectangle tourné de angle
+// autour de son centre.
+func CircleOBBCollision(cx, cy, cr, rx, ry, rw, rh, angle float64) bool {
+	mx, my := rx+rw/2, ry+rh/2
+	sin, cos := math.Sincos(-angle)
+	lx := (cx-mx)*cos - (cy-my)*sin
+	ly := (cx-mx)*sin + (cy-my)*cos
+	return CircleRectCollision(lx, ly, cr, -rw/2, -rh/2, rw, rh)
 }
-func IsAlphaNumeric(s string) bool {
-	for _, r := range s {
-		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
-			return false
+
+// SweptCircleRect renvoie le temps d'impact t (0..1) d'un cercle de rayon cr
+// qui se déplace de (cx, cy) à (cx+dx, cy+dy) contre un rectangle.
+func SweptCircleRect(cx, cy, dx, dy, cr, rx, ry, rw, rh float64) (float64, bool) {
+	best := math.Inf(1)
+	try := func(t float64) {
+		if t >= 0 && t <= 1 && t < best {
+			best = t
+		}
+	}
+
+	// --- faces du rectangle, décalées du rayon ---
+	if dx > 0 {
+		t := (rx - cr - cx) / dx
+		if y := cy + dy*t; y >= ry && y <= ry+rh {
+			try(t)
+		}
+	}
+	if dx < 0 {
+		t := (rx + rw + cr - cx) / dx
+		if y := cy + dy*t; y >= ry && y <= ry+rh {
+			try(t)
+		}
+	}
+	if dy > 0 {
+		t := (ry - cr - cy) / dy
+		if x := cx + dx*t; x >= rx && x <= rx+rw {
+			try(t)
+		}
+	}
+	if dy < 0 {
+		t := (ry + rh + cr - cy) / dy
+		if x := cx + dx*t; x >= rx && x <= rx+rw {
+			try(t)
+		}
+	}
+
+	// --- coins arrondis ---
+	a := dx*dx + dy*dy
+	if a > 0 {
+		for _, c := range [4][2]float64{{rx, ry}, {rx + rw, ry}, {rx, ry + rh}, {rx + rw, ry + rh}} {
+			ox, oy := cx-c[0], cy-c[1]
+			b := 2 * (ox*dx + oy*dy)
+			k := ox*ox + oy*oy - cr*cr
+			disc := b*b - 4*a*k
+			if disc < 0 {
+				continue
+			}
+			try((-b - math.Sqrt(disc)) / (2 * a))
 		}
 	}
-	return true
+
+	if math.IsInf(best, 1) {
+		return 0, false
+	}
+	return best, true
+}
+
+// SweptCircleOBB est SweptCircleRect contre un rectangle tourné de angle
+// autour de son centre.
+func SweptCircleOBB(cx, cy, dx, dy, cr, rx, ry, rw, rh, angle float64) (float64, bool) {
+	mx, my := rx+rw/2, ry+rh/2
+	sin, cos := math.Sincos(-angle)
+	lx := (cx-mx)*cos - (cy-my)*sin
+	ly := (cx-mx)*sin + (cy-my)*cos
+	ldx := dx*cos - dy*sin
+	ldy := dx*sin + dy*cos
+	return SweptCircleRect(lx, ly, ldx, ldy, cr, -rw/2, -rh/2, rw, rh)
 }
 func LoadSound(path string) (*audio.Player, error) {
 	data, err := os.ReadFile(path)
@@ -192,7 +253,7 @@ func LoadSound(path string) (*audio.Player, error) {
 			return nil, fmt.Errorf("cannot decode wav %q: %w", path, err)
 		}
 		fmt.Printf("Sound load with success!")
-		return audioContext.NewPlayer(stream)
+		return NewPannedPlayer(stream)
 		// return audio.NewPlayer(audioContext, stream)
 
 	case ".ogg", ".oga", ".vorbis":
@@ -201,7 +262,7 @@ func LoadSound(path string) (*audio.Player, error) {
 			return nil, fmt.Errorf("cannot decode ogg %q: %w", path, err)
 		}
 		fmt.Printf("Sound load with success!")
-		return audio.NewPlayer(audioContext, stream)
+		return NewPannedPlayer(stream)
 
 	case ".mp3":
 		d, err := mp3.NewDecoder(bytes.NewReader(data))
@@ -209,133 +270,245 @@ func LoadSound(path string) (*audio.Player, error) {
 			log.Fatal(err)
 		}
 
-		return audioContext.NewPlayer(d)
+		return NewPannedPlayer(d)
 
 	default:
 		return nil, fmt.Errorf("unsupported audio extension %q", ext)
 	}
 }
 
+// RestartRun recommence la course au niveau 1.
+func (g *Game) RestartRun() {
+	g.Level = 1
+	g.OpacityPlusOrNegative = true
+	g.PlayerLife = 3
+	g.TimeBeforeLevelDown = -67
+	g.Particles.Clear()
+	g.Entities = nil
+	g.SpaceCNT = 0
+	g.SlowMotion = false
+	g.StartTime = time.Time{}
+	g.endTime = 0
+	g.EndTimeIsSet = false
+	g.Opacity = 0
+	g.DataAreSave = false
+	g.RunMissedCollectible = false
+	g.ChangeLevelAnimation = false
+	g.StartLevel()
+	g.State = 3
+	g.Paused = false
+	input.Reset()
+}
+
+// StartLevel génère le niveau courant et y replace le joueur.
+func (g *Game) StartLevel() {
+	g.Generate_Level(g.Level)
+	g.Respawn()
+	g.Camera.Snap(g)
+}
+
+// Respawn replace le joueur au départ du niveau, ou dans le dernier
+// checkpoint touché.
+func (g *Game) Respawn() {
+	g.PlayerX, g.PlayerY = g.SpawnX, g.SpawnY
+	g.PlayerBarrel = nil
+	if g.SpawnBarrel != nil {
+		g.PlayerX, g.PlayerY = g.SpawnBarrel.SpawnPoint()
+		g.PlayerBarrel = g.SpawnBarrel
+	} else {
+		// le joueur part dans le baril du départ, qui l'emporte s'il bouge
+		for _, e := range g.Entities {
+			if b, ok := e.(barrelEntity); ok && b.barrel().Touches(g) {
+				g.PlayerBarrel = b.barrel()
+				break
+			}
+		}
+	}
+	if g.PlayerBarrel != nil {
+		g.AutoFireTimer = float64(g.PlayerBarrel.AutoFire)
+	}
+	g.PlayerMoved = false
+	g.ResetVelocity()
+}
+
+// vertical renvoie l'ancien aller-retour vertical des objets qui bougent.
+func vertical(down bool) *Path {
+	return NewPingPong(false, 80, 400, 2, down)
+}
+
 func (g *Game) Generate_Level(L int) {
+	g.SpawnX, g.SpawnY = 160, 240
+	g.SpawnBarrel = nil
+	g.LevelW, g.LevelH = ScreenW, ScreenH
+	if g.Sandbox {
+		g.sandboxLevel(L)
+		g.initLevel()
+		return
+	}
 	switch L {
 	case 1:
-		g.Barrels = []BarrelsS{
-			{50, 215, 100, 50, true, false, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{490, 215, 100, 50, true, false, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			NewGoalBarrel(490, 215),
 		}
-		g.Bouncers = []BouncersS{}
-		g.Obstacles = []ObstaclesS{}
-		return
 	case 2:
-		g.Barrels = []BarrelsS{
-			{50, 215, 100, 50, true, false, true, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{270, 215, 100, 50, true, true, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{490, 400, 100, 50, true, false, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
+		g.Entities = []Entity{
+			NewFragileBarrel(50, 215),
+			NewBarrel(270, 215).WithPath(vertical(true)),
+			NewGoalBarrel(490, 400),
+			NewBouncer(407, 300),
 		}
-		g.Bouncers = []BouncersS{
-			{407, 300, 50, 50, color.RGBA{58, 110, 165, 255}},
-		}
-		g.Obstacles = []ObstaclesS{}
-		return
 	case 3:
-		g.Barrels = []BarrelsS{
-			{50, 215, 100, 50, true, false, false, false, true, 0, 0, 100, color.RGBA{138, 43, 226, 255}},
-			{400, 81, 100, 50, true, true, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{490, 400, 100, 50, true, true, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
+		g.Entities = []Entity{
+			NewMagicBarrel(50, 215),
+			NewBarrel(400, 81).WithPath(vertical(true)),
+			NewGoalBarrel(490, 400).WithPath(vertical(true)),
+			NewObstacle(407, 350),
 		}
-		g.Obstacles = []ObstaclesS{
-			{407, 350, 50, 50, false, true, color.RGBA{178, 34, 34, 255}},
-		}
-		g.Bouncers = []BouncersS{}
-		return
 	case 4:
-		g.Barrels = []BarrelsS{
-			{50, 215, 100, 50, true, false, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{270, 81, 100, 50, true, true, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{490, 400, 100, 50, true, false, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-		}
-		g.Obstacles = []ObstaclesS{
-			{205, 81, 50, 50, true, true, color.RGBA{178, 34, 34, 255}},
-		}
-		g.Bouncers = []BouncersS{
-			{450, 215, 50, 50, color.RGBA{58, 110, 165, 255}},
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			NewBarrel(270, 81).WithPath(vertical(true)),
+			NewGoalBarrel(490, 400),
+			NewObstacle(205, 81).WithPath(vertical(true)),
+			NewBouncer(450, 215),
 		}
 	case 5:
-		g.Barrels = []BarrelsS{
-			{50, 215, 100, 50, true, true, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{270, 0, 100, 50, true, true, true, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{375, 250, 100, 50, false, true, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{490, 50, 100, 50, true, false, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-		}
-		g.Obstacles = []ObstaclesS{
-			{240, 300, 50, 50, true, false, color.RGBA{178, 34, 34, 255}},
-			{310, 50, 50, 50, false, false, color.RGBA{178, 34, 34, 255}},
+		g.Entities = []Entity{
+			NewBarrel(50, 215).WithPath(vertical(true)),
+			&FragileBarrel{Barrel: NewBarrel(270, 0).WithPath(vertical(true)), CoolDown: 100, Durability: 100},
+			NewBarrel(375, 250).WithPath(vertical(false)),
+			NewGoalBarrel(490, 50),
+			NewObstacle(240, 300).WithPath(vertical(false)),
+			NewObstacle(310, 50),
 		}
-		g.Bouncers = []BouncersS{}
 	case 6:
-		g.Barrels = []BarrelsS{
-			{50, 215, 100, 50, true, false, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{400, 215, 100, 50, true, false, false, true, false, 330, 75, 100, color.RGBA{139, 69, 19, 255}},
-			{250, 50, 100, 50, true, false, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{490, 50, 100, 50, true, false, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			NewTeleporterBarrel(400, 215, 330, 75),
+			NewBarrel(250, 50),
+			NewGoalBarrel(490, 50),
 		}
-		g.Obstacles = []ObstaclesS{}
-		g.Bouncers = []BouncersS{}
-
 	case 7:
-		g.Barrels = []BarrelsS{
-			{50, 375, 100, 50, true, false, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{350, 215, 100, 50, true, true, false, true, false, 530, 240, 100, color.RGBA{139, 69, 19, 255}},
-			{450, 215, 100, 50, false, true, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
-			{490, 50, 100, 50, true, false, false, false, false, 0, 0, 100, color.RGBA{139, 69, 19, 255}},
+		g.SpawnX, g.SpawnY = 160, 400
+		g.Entities = []Entity{
+			NewBarrel(50, 375),
+			&TeleporterBarrel{Barrel: NewBarrel(350, 215).WithPath(vertical(true)), ToX: 530, ToY: 240},
+			NewBarrel(450, 215).WithPath(vertical(false)),
+			NewGoalBarrel(490, 50),
+			NewObstacle(270, 300),
 		}
-		g.Obstacles = []ObstaclesS{
-			{270, 300, 50, 50, false, false, color.RGBA{178, 34, 34, 255}},
+	}
+	g.initLevel()
+}
+
+// initLevel remet à zéro ce qui dépend des entités du niveau.
+func (g *Game) initLevel() {
+	g.Keys = map[string]int{}
+	g.UpdateSignals()
+
+	g.LevelCollected = 0
+	g.LevelCollectibles = 0
+	for _, e := range g.Entities {
+		if _, ok := e.(*Collectible); ok {
+			g.LevelCollectibles++
 		}
-		g.Bouncers = []BouncersS{}
 	}
 }
-func (g *Game) DrawLifes(screen *ebiten.Image) error {
-	op := &text.DrawOptions{}
-	op.GeoM.Translate(float64(200), float64(50))
-	op.ColorScale.ScaleWithColor(color.RGBA{222, 49, 99, 0})
-	text.Draw(screen, fmt.Sprintf("Vies :%d", g.PlayerLife), &text.GoTextFace{
-		Source: mplusFaceSource,
-		Size:   34,
-	}, op)
+
+// HUDMargin est la marge du HUD autour du canvas, HUDLine la hauteur d'une
+// ligne de texte en taille 34.
+const (
+	HUDMargin = 10
+	HUDLine   = 40
+)
+
+func (g *Game) DrawLifes(h HUD) error {
+	h.Text(TN(MsgLives, g.PlayerLife, g.PlayerLife), 34, AnchorTop, 0, HUDLine, RoleColor(RoleHUDText))
+	return nil
+}
+func (g *Game) DrawCollectibles(h HUD) error {
+	if g.LevelCollectibles == 0 {
+		return nil
+	}
+	s := fmt.Sprintf("%d/%d", g.LevelCollected, g.LevelCollectibles)
+	// icône juste à gauche du compteur, quelle que soit sa largeur
+	h.Circle(AnchorTopRight, -HUDMargin-h.TextWidth(s, 34)-20, HUDLine+17, 12, RoleColor(RoleCoin))
+	h.Text(s, 34, AnchorTopRight, -HUDMargin, HUDLine, RoleColor(RoleHUDText))
+	return nil
+}
+func (g *Game) DrawKeys(screen *ebiten.Image) error {
+	x := 14.0
+	seen := map[string]bool{}
+	for _, e := range g.Entities {
+		// une icône par clé en poche, de la couleur de la serrure qu'elle ouvre
+		if gt, ok := e.(*Gate); ok && gt.KeyID != "" && !seen[gt.KeyID] {
+			seen[gt.KeyID] = true
+			for i := 0; i < g.Keys[gt.KeyID]; i++ {
+				DrawKey(screen, x, 34, gt.KeyColor)
+				x += 18
+			}
+		}
+	}
 	return nil
 }
-func (g *Game) DrawTimer(screen *ebiten.Image) error {
+func (g *Game) DrawTimer(h HUD) error {
+	var s string
 	if g.SpaceCNT == 0 {
-		ebitenutil.DebugPrintAt(screen, "Time: 0:0:00", 5, 5)
-	} else if g.Level != 8 {
-		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Time: %s", time.Since(g.StartTime).Round(10*time.Millisecond)), 5, 5)
+		s = T(MsgTime, "0:0:00")
+	} else if g.Level <= g.LevelCount() {
+		s = T(MsgTime, g.RunTime().Round(10*time.Millisecond))
 	} else if g.State == 5 {
-		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Time: %s", g.endTime), 5, 5)
+		s = T(MsgTime, g.endTime)
 	}
+	h.Text(s, 14, AnchorTopLeft, HUDMargin/2, HUDMargin/2, color.White)
 	return nil
 }
-func (g *Game) DrawLevel(screen *ebiten.Image) error {
-	op := &text.DrawOptions{}
-	op.GeoM.Translate(float64(200), float64(90))
-	op.ColorScale.ScaleWithColor(color.RGBA{222, 49, 99, 0})
-	text.Draw(screen, fmt.Sprintf("Level :%d", g.Level), &text.GoTextFace{
-		Source: mplusFaceSource,
-		Size:   34,
-	}, op)
+func (g *Game) DrawLevel(h HUD) error {
+	h.Text(T(MsgLevel, g.Level), 34, AnchorTop, 0, 2*HUDLine, RoleColor(RoleHUDText))
 	return nil
 }
-func (g *Game) DrawRestartButton(screen *ebiten.Image) error {
-	ebitenutil.DrawRect(screen, 170, 183, 312, 63, color.RGBA{0, 255, 0, 255})
-	op := &text.DrawOptions{}
-	op.GeoM.Translate(float64(171), float64(190))
-	op.ColorScale.ScaleWithColor(color.RGBA{255, 255, 255, 255})
-	text.Draw(screen, "Restart", &text.GoTextFace{
-		Source: mplusFaceSource,
-		Size:   45,
-	}, op)
+
+// RestartButton renvoie le rectangle du bouton Restart dans le canvas.
+func RestartButton() (float64, float64, float64, float64) {
+	return ScreenW/2 - 156, ScreenH/2 - 57, 312, 63
+}
+
+func (g *Game) DrawRestartButton(h HUD) error {
+	x, y, w, ht := RestartButton()
+	h.Rect(AnchorTopLeft, x, y, w, ht, color.RGBA{0, 255, 0, 255})
+	h.Text(T(MsgRestart), 45, AnchorCenter, x+w/2-ScreenW/2, y+ht/2-ScreenH/2, color.RGBA{255, 255, 255, 255})
 	return nil
 }
+
+// DrawRotatedRect dessine un rectangle plein tourné autour de son centre.
+func DrawRotatedRect(screen *ebiten.Image, x, y, w, h, angle float64, clr color.Color) {
+	if whitePixel == nil {
+		whitePixel = ebiten.NewImage(1, 1)
+		whitePixel.Fill(color.White)
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(w, h)
+	op.GeoM.Translate(-w/2, -h/2)
+	op.GeoM.Rotate(angle)
+	op.GeoM.Translate(x+w/2, y+h/2)
+	op.ColorScale.ScaleWithColor(clr)
+	screen.DrawImage(whitePixel, op)
+}
+
+// DrawCountdownRing dessine un anneau qui se vide avec frac (1 = plein, 0 = vide).
+func DrawCountdownRing(screen *ebiten.Image, cx, cy, r, frac float64, clr color.Color) {
+	if frac <= 0 {
+		return
+	}
+	start := -math.Pi / 2
+	var path vector.Path
+	path.Arc(float32(cx), float32(cy), float32(r), float32(start), float32(start+2*math.Pi*math.Min(frac, 1)), vector.Clockwise)
+	op := &vector.DrawPathOptions{AntiAlias: true}
+	op.ColorScale.ScaleWithColor(clr)
+	vector.StrokePath(screen, &path, &vector.StrokeOptions{Width: 4}, op)
+}
+
 func AnimateBackground(backgroundX, backgroundY, backgroundW, backgroundH float64) (float64, float64, float64, float64) {
 	// Animation en hauteur
 	if backgroundH < 480 {
@@ -352,20 +525,28 @@ func AnimateBackground(backgroundX, backgroundY, backgroundW, backgroundH float6
 	return backgroundX, backgroundY, backgroundW, backgroundH
 }
 
-func (g *Game) DrawTop5(screen *ebiten.Image) error {
-	ebitenutil.DrawRect(screen, 50, 300, 500, 100, color.RGBA{0, 255, 0, 255})
-	for i, s := range g.Save.Top5 {
-		op := &text.DrawOptions{}
-		op.GeoM.Translate(float64(100), float64(20*i+300))
-		op.ColorScale.ScaleWithColor(color.RGBA{255, 255, 255, 255})
-		text.Draw(screen, fmt.Sprintf("%d. %v. - %s", i+1, s.Time, s.UserName), &text.GoTextFace{
-			Source: mplusFaceSource,
-			Size:   20,
-		}, op)
-	}
+func (g *Game) DrawTop5Full(h HUD) error {
+	DrawScoreList(h, T(MsgFullCompletion), g.Save.Top5Full, 60, 120, color.RGBA{255, 200, 0, 255})
+	return nil
+}
+
+func (g *Game) DrawTop5(h HUD) error {
+	DrawScoreList(h, "", g.Save.Top5, 300, 100, color.RGBA{0, 255, 0, 255})
 	return nil
 }
 
+// DrawScoreList dessine un classement dans un panneau centré, en haut à y.
+func DrawScoreList(h HUD, title string, scores []Score, y, height float64, clr color.Color) {
+	h.Rect(AnchorTop, -250, y, 500, height, clr)
+	if title != "" {
+		h.Text(title, 20, AnchorTopLeft, ScreenW/2-240, y, color.RGBA{255, 255, 255, 255})
+		y += 20
+	}
+	for i, s := range scores {
+		h.Text(T(MsgScoreLine, i+1, s.Time, s.UserName), 20, AnchorTopLeft, ScreenW/2-200, y+float64(20*i), color.RGBA{255, 255, 255, 255})
+	}
+}
+
 func SaveToDisk(data SaveData, filename string) error {
 	bytes, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
@@ -391,6 +572,76 @@ func Within(px, py, rx, ry, rw, rh float64) bool {
 	return px >= rx && px <= rx+rw && py >= ry && py <= ry+rh
 }
 
+// AddScore ajoute le score au classement : un joueur déjà présent ne garde
+// que son meilleur temps, puis on garde les 5 meilleurs.
+func AddScore(top []Score, s Score) []Score {
+	nameExist := false
+	for _, score := range top {
+		if s.UserName == score.UserName {
+			nameExist = true
+			break
+		}
+	}
+
+	if !nameExist {
+		// Ajout normal
+		top = append(top, s)
+	} else {
+
+		// --- 🔥 VERSION QUI SUPPRIME L’ANCIEN SCORE AVANT D'AJOUTER LE NOUVEAU ---
+		for i, score := range top {
+			if s.UserName == score.UserName {
+
+				if score.Time > s.Time {
+
+					//delete l'ancien score
+					top = append(top[:i], top[i+1:]...)
+
+					// Ajouter le nouveau score
+					top = append(top, s)
+				}
+
+				break
+			}
+		}
+	}
+
+	// Trier et garder seulement les 5 meilleurs
+	slices.SortFunc(top, CmpTime)
+	if len(top) > 5 {
+		top = top[:5]
+	}
+	return top
+}
+
+// RecordCollectibles garde le meilleur nombre d'objets ramassés du niveau
+// pour le joueur courant, à la fin du niveau.
+func (g *Game) RecordCollectibles(level int) {
+	if g.Sandbox {
+		return
+	}
+	if g.LevelCollected < g.LevelCollectibles {
+		g.RunMissedCollectible = true
+	}
+	if g.LevelCollectibles == 0 {
+		return
+	}
+	if g.Save.Collected == nil {
+		g.Save.Collected = map[string]map[int]int{}
+	}
+	best := g.Save.Collected[g.currentUserName]
+	if best == nil {
+		best = map[int]int{}
+		g.Save.Collected[g.currentUserName] = best
+	}
+	if g.LevelCollected > best[level] {
+		best[level] = g.LevelCollected
+		// enregistré tout de suite, pour ne pas le perdre si la partie
+		// n'est pas finie
+		SaveToDisk(g.Save, "save.json")
+	}
+}
+
 func CmpTime(a, b Score) int {
 	if a.Time < b.Time {
 		return -1
@@ -401,10 +652,175 @@ func CmpTime(a, b Score) int {
 	return 1
 }
 
+// LandingRect renvoie la zone d'atterrissage d'un baril selon la direction du joueur.
+// Un tir droit vers la droite atterrit au bord du baril, toute autre direction
+// atterrit dès que le joueur touche le baril.
+func (g *Game) LandingRect(b *Barrel) (float64, float64, float64, float64) {
+	if g.PlayerVX <= 0 || g.PlayerVY != 0 {
+		return b.X - 10, b.Y, b.W, b.H
+	}
+	return b.X + 125, b.Y, 0, b.H
+}
+
+// CanLandIn empêche le joueur de retomber dans le baril qu'il vient de
+// quitter, tant qu'il n'en est pas sorti (voir StepPlayer).
+func (g *Game) CanLandIn(b *Barrel) bool {
+	return b != g.PlayerBarrel || g.PlayerLeftBarrel
+}
+
+// StepPlayer déplace le joueur lancé puis le fait réagir aux entités.
+func (g *Game) StepPlayer() {
+	if g.PlayerMoved {
+		g.MovePlayer(g.PlayerVX, g.PlayerVY)
+		// sorti du baril et de sa zone d'atterrissage : il peut y revenir
+		if b := g.PlayerBarrel; b != nil && !b.Touches(g) {
+			x, y, w, h := g.LandingRect(b)
+			if !CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, x, y, w, h) {
+				g.PlayerLeftBarrel = true
+			}
+		}
+	}
+
+	// --- ENTITÉS : COLLISIONS AVEC LE PLAYER ---
+	// pendant le changement de niveau, le joueur attend dans le baril d'arrivée
+	if !g.ChangeLevelAnimation {
+		for _, e := range g.Entities {
+			e.CollidePlayer(g)
+		}
+	}
+	g.flushRemovedEntities()
+}
+
+// Rider renvoie le baril qui porte le joueur, nil s'il est lancé.
+func (g *Game) Rider() *Barrel {
+	if g.PlayerMoved {
+		return nil
+	}
+	return g.PlayerBarrel
+}
+
+// CurrentBarrel renvoie le baril où le joueur attend d'être lancé, ou nil.
+func (g *Game) CurrentBarrel() *Barrel {
+	if g.PlayerBarrel == nil || !g.PlayerBarrel.Touches(g) {
+		return nil
+	}
+	return g.PlayerBarrel
+}
+
+// LaunchAngle renvoie la direction de tir du baril où se trouve le joueur.
+func (g *Game) LaunchAngle() float64 {
+	if b := g.CurrentBarrel(); b != nil {
+		return b.Angle
+	}
+	return 0
+}
+
+// Launch tire le joueur hors de son baril.
+func (g *Game) Launch() {
+	angle := g.LaunchAngle()
+	if g.SlowMotion {
+		g.PlayerX += 40 * math.Cos(angle)
+		g.PlayerY += 40 * math.Sin(angle)
+	}
+	g.PlayerVX = g.PlayerSpeed * math.Cos(angle)
+	g.PlayerVY = g.PlayerSpeed * math.Sin(angle)
+	// un baril en mouvement donne son élan au joueur
+	if b := g.CurrentBarrel(); b != nil {
+		vx, vy := b.Velocity()
+		g.PlayerVX += vx
+		g.PlayerVY += vy
+		g.PlayerSpeed = math.Hypot(g.PlayerVX, g.PlayerVY)
+	}
+	g.PlayerLeftBarrel = false
+	g.barrelShootSound.Rewind()
+	g.barrelShootSound.Play()
+	g.PlayerMoved = true
+	g.SpaceCNT++
+	if g.SpaceCNT == 1 {
+		g.State++
+		g.RaceStartSound.Rewind()
+		g.RaceStartSound.Play()
+		g.StartTime = time.Now()
+	}
+}
+
+// TimeScale renvoie l'avance d'un tick pour les objets, réduite en slow motion.
+func (g *Game) TimeScale() float64 {
+	if g.SlowMotion {
+		return SlowMotionFactor
+	}
+	return 1
+}
+
+// ResetVelocity remet la vitesse de lancement par défaut, vers la droite.
+func (g *Game) ResetVelocity() {
+	g.PlayerSpeed = LaunchSpeed
+	g.PlayerVX, g.PlayerVY = LaunchSpeed, 0
+}
+
+// SetPlayerSpeed change la vitesse du joueur sans changer sa direction.
+func (g *Game) SetPlayerSpeed(speed float64) {
+	if v := math.Hypot(g.PlayerVX, g.PlayerVY); v > 0 {
+		g.PlayerVX *= speed / v
+		g.PlayerVY *= speed / v
+	}
+	g.PlayerSpeed = speed
+}
+
+// MovePlayer déplace le joueur de (dx, dy) en s'arrêtant au premier contact
+// avec une entité Solid, pour ne jamais la traverser.
+// Les objets déjà touchés au départ sont ignorés.
+func (g *Game) MovePlayer(dx, dy float64) {
+	t := 1.0
+	sweep := func(rx, ry, rw, rh float64) {
+		if CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, rx, ry, rw, rh) {
+			return
+		}
+		// rayon un peu réduit : le point de contact compte toujours comme une collision
+		if toi, hit := SweptCircleRect(g.PlayerX, g.PlayerY, dx, dy, PlayerR-0.01, rx, ry, rw, rh); hit && toi < t {
+			t = toi
+		}
+	}
+	for _, e := range g.Entities {
+		switch solid := e.(type) {
+		case RotatedSolid:
+			x, y, w, h, angle, ok := solid.SweepOBB(g)
+			if !ok || CircleOBBCollision(g.PlayerX, g.PlayerY, PlayerR, x, y, w, h, angle) {
+				continue
+			}
+			if toi, hit := SweptCircleOBB(g.PlayerX, g.PlayerY, dx, dy, PlayerR-0.01, x, y, w, h, angle); hit && toi < t {
+				t = toi
+			}
+		case Solid:
+			if x, y, w, h, ok := solid.SweepRect(g); ok {
+				sweep(x, y, w, h)
+			}
+		}
+	}
+	g.PlayerX += dx * t
+	g.PlayerY += dy * t
+}
+
 func (g *Game) Update() error {
-	if g.TUNE > 0 {
-		g.TUNE--
+	if g.MouseAsTouch {
+		input.SimulateMouseTouch(&g.Display)
 	}
+	input.Buttons = input.Buttons[:0]
+	if (g.State == 3 || g.State == 4) && !g.Paused && input.TouchUsed {
+		x, y, w, h := PauseButton()
+		input.Buttons = append(input.Buttons, TouchButton{X: x, Y: y, W: w, H: h, Action: ActionPause})
+	}
+	input.Update(g.Display.ToCanvas)
+	g.UpdateDisplay()
+	if g.Paused {
+		g.UpdatePause()
+		return nil
+	}
+	if (g.State == 3 || g.State == 4) && IsActionJustPressed(ActionPause) {
+		g.Pause()
+		return nil
+	}
+	g.AnimTick += g.TimeScale()
 	if g.TSICM > 0 {
 		g.TSICM--
 	}
@@ -422,27 +838,8 @@ func (g *Game) Update() error {
 	if g.slowMotionCooldown > 0 {
 		g.slowMotionCooldown--
 	}
-	if g.TimeSaveAnimation == 1 && g.State == 1 {
-		if len(g.currentUserName) > 7 {
-			g.ValidUserName = 1
-			g.TUNE = 80
-			g.TimeSaveAnimation = 70
-			g.State = 0
-		}
-		if !IsAlphaNumeric(g.currentUserName) {
-			g.ValidUserName = 2
-			g.TUNE = 80
-			g.TimeSaveAnimation = 70
-			g.State = 0
-		}
-		if len(g.currentUserName) == 0 {
-			g.ValidUserName = 3
-			g.TUNE = 80
-			g.TimeSaveAnimation = 70
-			g.State = 0
-		}
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyControlLeft) {
+	// Ctrl efface la sauvegarde, sauf pendant la saisie (Ctrl+V colle)
+	if ebiten.IsKeyPressed(ebiten.KeyControlLeft) && g.State > 2 {
 		os.Remove("save.json")
 		g.Save = SaveData{}
 	}
@@ -450,8 +847,7 @@ func (g *Game) Update() error {
 		if g.player.Volume() < 0.4 {
 			g.player.SetVolume(g.player.Volume() + 0.004)
 		}
-		xC, yC := ebiten.CursorPosition()
-		x, y := float64(xC), float64(yC)
+		x, y := g.Display.CursorPosition()
 		if g.ChangeLevelAnimation && g.Opacity > 255 {
 			g.OpacityPlusOrNegative = false
 		}
@@ -466,32 +862,10 @@ func (g *Game) Update() error {
 			g.Opacity += 2
 		}
 		if g.State == 5 {
-			if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-				if Within(x, y, 170, 183, 312, 63) {
-					g.Level = 1
-					g.PlayerY = 240
-					g.PlayerX = 160
-					g.PlayerSpeed = 15
-					g.OpacityPlusOrNegative = true
-					g.PlayerLife = 3
-					g.TimeBeforeLevelDown = -67
-					g.Particles = nil
-					g.Barrels = nil
-					g.Obstacles = nil
-					g.Bouncers = nil
-					g.SpaceCNT = 0
-					g.PlayerBarrel = 0
-					g.PlayerMoved = false
-					g.SlowMotion = false
-					g.StartTime = time.Time{}
-					g.endTime = 0
-					g.EndTimeIsSet = false
-					g.Opacity = 0
-					g.DataAreSave = false
-					g.ChangeLevelAnimation = false
-					g.Generate_Level(g.Level)
-					g.State = 3
-				}
+			bx, by, bw, bh := RestartButton()
+			if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) && Within(x, y, bx, by, bw, bh) || input.Tapped(bx, by, bw, bh) ||
+				IsActionJustPressed(ActionConfirm) || IsActionJustPressed(ActionRestart) {
+				g.RestartRun()
 			}
 		}
 		if g.ChangeLevelAnimation && g.Opacity <= 0 {
@@ -499,14 +873,8 @@ func (g *Game) Update() error {
 			g.Levelplus.Play()
 			g.ChangeLevelAnimation = false
 			g.Opacity = 0
-			if g.Level != 7 {
-				g.PlayerX = 160
-				g.PlayerY = 240
-			} else {
-				g.PlayerX = 160
-				g.PlayerY = 400
-			}
-			g.PlayerSpeed = 15
+			g.Respawn()
+			g.Camera.Snap(g)
 			g.OpacityPlusOrNegative = true
 		}
 		if g.State == 5 && !g.EndTimeIsSet {
@@ -516,53 +884,20 @@ func (g *Game) Update() error {
 		if g.State == 5 && !g.DataAreSave {
 			g.WinSound.Rewind()
 			g.WinSound.Play()
-			nameExist := false
-			for _, score := range g.Save.Top5 {
-				if g.currentUserName == score.UserName {
-					nameExist = true
-					break
-				}
+			score := Score{
+				Time:     g.endTime,
+				UserName: g.currentUserName,
+				Code:     g.CurrentCode,
 			}
-
-			if !nameExist {
-				// Ajout normal
-				g.Save.Top5 = append(g.Save.Top5, Score{
-					Time:     g.endTime,
-					UserName: g.currentUserName,
-					Code:     g.CurrentCode,
-				})
-			} else {
-
-				// --- 🔥 VERSION QUI SUPPRIME L’ANCIEN SCORE AVANT D'AJOUTER LE NOUVEAU ---
-				for i, score := range g.Save.Top5 {
-					if g.currentUserName == score.UserName {
-
-						if score.Time > g.endTime {
-
-							//delete l'ancien score
-							g.Save.Top5 = append(g.Save.Top5[:i], g.Save.Top5[i+1:]...)
-
-							// Ajouter le nouveau score
-							g.Save.Top5 = append(g.Save.Top5, Score{
-								Time:     g.endTime,
-								UserName: g.currentUserName,
-								Code:     g.CurrentCode,
-							})
-						}
-
-						break
-					}
+			if !g.Sandbox {
+				g.Save.Top5 = AddScore(g.Save.Top5, score)
+				if !g.RunMissedCollectible {
+					g.Save.Top5Full = AddScore(g.Save.Top5Full, score)
 				}
-			}
 
-			// Trier et garder seulement les 5 meilleurs
-			slices.SortFunc(g.Save.Top5, CmpTime)
-			if len(g.Save.Top5) > 5 {
-				g.Save.Top5 = g.Save.Top5[:5]
+				// Mettre à jour le joueur
+				SaveToDisk(g.Save, "save.json")
 			}
-
-			// Mettre à jour le joueur
-			SaveToDisk(g.Save, "save.json")
 			g.DataAreSave = true
 
 		}
@@ -570,284 +905,68 @@ func (g *Game) Update() error {
 			g.loseSound.Rewind()
 			g.loseSound.Play()
 			g.Level = 1
-			g.PlayerX = 160
-			g.PlayerY = 240
+			g.RunMissedCollectible = false
 			g.PlayerLife = 3
-			g.PlayerSpeed = 15
 			g.SlowMotion = false
-			defer g.Generate_Level(g.Level)
+			g.StartLevel()
 		}
 		if g.TimeBeforeLevelDown > 0 {
 			g.TimeBeforeLevelDown--
 		}
 		if g.TimeBeforeLevelDown == 0 && !g.ChangeLevelAnimation {
 			g.Level--
-			if g.Level != 7 {
-				g.PlayerX = 160
-				g.PlayerY = 240
-			} else {
-				g.PlayerX = 160
-				g.PlayerY = 400
-			}
-			g.PlayerSpeed = 15
-			defer g.Generate_Level(g.Level)
+			g.StartLevel()
 			g.TimeBeforeLevelDown = -67
 		}
-		// --- BORDS DE L'ÉCRAN ---
+		// --- BORDS DU NIVEAU ---
 		if (g.PlayerX-PlayerR < 0 ||
-			g.PlayerX+PlayerR > 640 ||
+			g.PlayerX+PlayerR > g.LevelW ||
 			g.PlayerY-PlayerR < 0 ||
-			g.PlayerY+PlayerR > 480) &&
+			g.PlayerY+PlayerR > g.LevelH) &&
 			!g.ChangeLevelAnimation {
 
-			if g.Level != 7 {
-				g.PlayerX = 160
-				g.PlayerY = 240
-			} else {
-				g.PlayerX = 160
-				g.PlayerY = 400
-			}
-			g.PlayerSpeed = 15
+			g.Respawn()
 			g.PlayerLife--
 			g.loseSound2.Rewind()
 			g.loseSound2.Play()
 		}
 
-		// --- SUPPRESSION DES BARILS ---
-		deleteBarrels := []int{} // tableau vide OK
-
-		for i := range g.Barrels {
-			b := &g.Barrels[i] // pointeur = on modifie réellement le slice
-
-			// --- Barils qui bougent verticalement ---
-			if b.Moved {
-
-				if b.y > 400 {
-					b.BarrelsDirY = false
-				}
-				if b.y < 80 {
-					b.BarrelsDirY = true
-				}
-
-				if b.BarrelsDirY {
-					if g.SlowMotion {
-						b.y += 0.7
-					} else {
-						b.y += 2
-					}
-				} else {
-					if g.SlowMotion {
-						b.y -= 0.7
-					} else {
-						b.y -= 2
-					}
-				}
-
-				// Player sur le baril = il reste dessus
-				if CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, b.x, b.y, b.w, b.h) {
-					g.PlayerY = b.y + 25
-				}
-			}
-			// ---- BARILS MAGIQUES ----
-			if b.Magic && CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, b.x, b.y, b.w, b.h) {
-				if !g.slowMotionAnimation {
-					g.slowMotionAnimation = true
-					g.slowMotionCooldown = 60
-				}
-				if !g.ChangeLevelAnimation {
-					if !g.SlowMotion {
-						g.slowMotionSound.Rewind()
-						g.slowMotionSound.Play()
-					}
-					g.SlowMotion = true
-					if g.PlayerSpeed > 0 {
-						g.PlayerSpeed = 6 // Vitesse réduite positive
-					} else {
-						g.PlayerSpeed = -6 // Vitesse réduite négative
-					}
-				}
-			}
-
-			// ---- BARILS FRAGILES ----
-			if b.fragile && CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, b.x, b.y, b.w, b.h) {
-				b.CoolDown--
-				b.color = color.RGBA{255, 0, 0, 255}
-				if b.CoolDown == 80 {
-					g.crackSound.Rewind()
-					g.crackSound.Play()
-				}
-				if b.CoolDown <= 0 && !g.ChangeLevelAnimation {
-					g.ExplosionSound.Rewind()
-					g.ExplosionSound.Play()
-					g.TimeBeforeLevelDown = 45
-					g.SpawnBarrelExplosion(b.x, b.y)
-					deleteBarrels = append(deleteBarrels, i)
-					if g.SlowMotion {
-						b.CoolDown = 235
-					} else {
-						b.CoolDown = 100
-					}
-				}
-			}
-			if b.Teleporter && CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, b.x, b.y, b.w, b.h) {
-				g.teleportSound.Rewind()
-				g.teleportSound.Play()
-				g.PlayerX = b.TeleporterX
-				g.PlayerY = b.TeleporterY
-			}
-			if !CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, b.x, b.y, b.w, b.h) {
-				b.color = color.RGBA{139, 69, 19, 255}
-			}
-		}
-
-		// --- ON SUPPRIME EN PARTANT DE LA FIN ---
-		for i := len(deleteBarrels) - 1; i >= 0; i-- {
-			g.Barrels = slices.Delete(g.Barrels, deleteBarrels[i], deleteBarrels[i]+1)
-		}
-
-		// --- OBSTACLES ---
-		for i := range g.Obstacles {
-			o := &g.Obstacles[i]
-
-			if o.Moved {
-
-				if o.y > 400 {
-					o.ObstaclesDirY = false
-				}
-				if o.y < 80 {
-					o.ObstaclesDirY = true
-				}
-
-				if o.ObstaclesDirY {
-					o.y += 2
-				} else {
-					o.y -= 2
-				}
-			}
-			if CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, o.x, o.y, o.w, o.h) && !g.ChangeLevelAnimation {
-				if g.Level != 7 {
-					g.PlayerX = 160
-					g.PlayerY = 240
-				} else {
-					g.PlayerX = 160
-					g.PlayerY = 400
-				}
-				g.PlayerLife--
-				g.PlayerSpeed = 15
-				g.loseSound2.Rewind()
-				g.loseSound2.Play()
-				g.hitSound.Rewind()
-				g.hitSound.Play()
-			}
+		// --- ENTITÉS : MOUVEMENT ---
+		g.UpdateSignals()
+		for _, e := range g.Entities {
+			e.Update(g)
 		}
-		// --- COLLISIONS AVEC BARILS ---
-		for _, b := range g.Barrels {
-			x := b.x + 125
-			w := b.w - 125
-
-			if g.PlayerSpeed == -15 {
-				x = b.x - 10
-				w = b.w
-			}
 
-			if CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, x, b.y, w, b.h) && g.PlayerMoved {
-				g.PlayerMoved = false
-				if !b.Magic {
-					g.SlowMotion = false
-					g.PlayerSpeed = 15
-				}
-				if !g.PlayerMoved && !b.Magic {
-					g.SlowMotion = false
-				}
-				// --- CHANGER DE NIVEAU ---
-				if b.x == 490 && !g.ChangeLevelAnimation {
-					g.Level++
-					g.SlowMotion = false
-					g.WinSound.Rewind()
-					g.WinSound.Play()
-					g.ChangeLevelAnimation = true
-					if g.Level != 7 {
-						g.PlayerX = 160
-						g.PlayerY = 240
-					} else {
-						g.PlayerX = 160
-						g.PlayerY = 400
-					}
-					g.PlayerSpeed = 15
-				}
-			}
+		// --- BARILS À TIR AUTOMATIQUE ---
+		autoBarrel, autoFire := false, false
+		if b := g.CurrentBarrel(); b != nil && b.AutoFire > 0 && !g.PlayerMoved {
+			autoBarrel = true
+			g.AutoFireTimer -= g.TimeScale()
+			autoFire = g.AutoFireTimer <= 0
 		}
 		// --- MOUVEMENT DU PLAYER ---
-		if ebiten.IsKeyPressed(ebiten.KeySpace) && !g.PlayerMoved && g.Opacity <= 0 {
-			if g.SlowMotion {
-				g.PlayerX += 40
-			}
-			g.barrelShootSound.Rewind()
-			g.barrelShootSound.Play()
-			g.PlayerMoved = true
-			g.SpaceCNT++
-			if g.SpaceCNT == 1 {
-				g.State++
-				g.RaceStartSound.Rewind()
-				g.RaceStartSound.Play()
-				g.StartTime = time.Now()
-			}
+		// un tir demandé juste avant l'atterrissage part à l'atterrissage
+		if !g.PlayerMoved && g.Opacity <= 0 && (autoFire || !autoBarrel && ConsumeAction(ActionLaunch)) {
+			g.Launch()
 		}
-		if g.PlayerMoved {
-			g.PlayerX += g.PlayerSpeed
-		}
-
-		// --- BOUNCERS ---
-		for _, boun := range g.Bouncers {
-			if CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, boun.x, boun.y, boun.w, boun.h) {
-				g.PlayerSpeed = -15
-				if g.BouncerSoundCooldown <= 0 {
-					g.BouncerSound.Rewind()
-					g.BouncerSound.Play()
-					g.BouncerSoundCooldown = 25
-				}
-			}
+		g.StepPlayer()
+		if g.WarpTimer > 0 {
+			g.WarpTimer -= g.TimeScale()
 		}
-		for i := 0; i < len(g.Particles); i++ {
-			p := &g.Particles[i]
-
-			p.x += p.vx
-			p.y += p.vy
-			p.vy += 0.1 // gravité légère
 
-			p.life--
-			if p.life <= 0 {
-				g.Particles = append(g.Particles[:i], g.Particles[i+1:]...)
-				i--
-			}
-		}
+		g.Particles.Update(g.TimeScale())
+		g.Camera.Update(g)
 	}
 	if g.State == 0 {
-		// 1. Récupérer le texte tapé cette frame
-		typed := ebiten.InputChars()
-		if len(typed) > 0 {
-			g.currentUserName += string(typed)
-		}
-
-		// 2. Supprimer un caractère avec Backspace
-		if ebiten.IsKeyPressed(ebiten.KeyBackspace) && len(g.currentUserName) > 0 {
-			g.currentUserName = g.currentUserName[:len(g.currentUserName)-1]
-		}
-
-		// 3. Fin de la saisie si l’utilisateur appuie sur Enter
-		if ebiten.IsKeyPressed(ebiten.KeyEnter) {
-			g.EnterCNT++
-			if g.EnterCNT == 1 {
-				fmt.Println("Nom terminé:", g.currentUserName)
-				g.State++
-			}
-		} else {
-			g.EnterCNT = 0
+		// fin de la saisie quand le joueur valide un pseudo correct avec Enter
+		if g.NameField.Update() || g.Keyboard.Update(g.NameField) {
+			g.currentUserName = g.NameField.String()
+			g.State++
 		}
 	}
 
 	if g.State == 2 {
-		// 1. si le nom n'existe pas alors on peut créer un code
+		// si le nom n'existe pas alors on peut créer un code
 		canCreateCode := true
 		for _, score := range g.Save.Top5 {
 			if g.currentUserName == score.UserName {
@@ -855,221 +974,178 @@ func (g *Game) Update() error {
 				break
 			}
 		}
-		if canCreateCode {
-			// 2. Récupérer le texte tapé cette frame
-			typed := ebiten.InputChars()
-			if len(typed) > 0 {
-				g.CurrentCode += string(typed)
-			}
-
-			// 3. Supprimer un caractère avec Backspace
-			if ebiten.IsKeyPressed(ebiten.KeyBackspace) && len(g.CurrentCode) > 0 {
-				g.CurrentCode = g.CurrentCode[:len(g.CurrentCode)-1]
-			}
-
-			// 4. Fin de la saisie si l’utilisateur appuie sur Enter
-			if ebiten.IsKeyPressed(ebiten.KeyEnter) {
-				g.EnterCNT++
-				if g.EnterCNT == 1 {
-					fmt.Println("Code terminé:", g.CurrentCode)
-					g.State++
-				}
-			} else {
-				g.EnterCNT = 0
-			}
-		} else {
-			// 2. Récupérer le texte tapé cette frame
-			typed := ebiten.InputChars()
-			if len(typed) > 0 {
-				g.CurrentCode += string(typed)
-			}
-
-			// 3. Supprimer un caractère avec Backspace
-			if ebiten.IsKeyPressed(ebiten.KeyBackspace) && len(g.CurrentCode) > 0 {
-				g.CurrentCode = g.CurrentCode[:len(g.CurrentCode)-1]
+		if g.CodeField.Update() || g.Keyboard.Update(g.CodeField) {
+			g.CurrentCode = g.CodeField.String()
+			if canCreateCode {
+				// le doigt posé sur OK ne doit pas lancer le joueur
+				input.Reset()
+				g.State++
+				return nil
 			}
-
-			// 4. Fin de la saisie si l’utilisateur appuie sur Enter
-			if ebiten.IsKeyPressed(ebiten.KeyEnter) {
-				g.EnterCNT++
-				if g.EnterCNT == 1 {
-					for _, score := range g.Save.Top5 {
-						if score.UserName == g.currentUserName {
-							if g.CurrentCode == score.Code {
-								g.State++
-								return nil
-							} else {
-								g.InvalidCode = true
-								g.TSICM = 60
-							}
-						}
+			for _, score := range g.Save.Top5 {
+				if score.UserName == g.currentUserName {
+					if g.CurrentCode == score.Code {
+						input.Reset()
+						g.State++
+						return nil
+					} else {
+						g.InvalidCode = true
+						g.TSICM = 60
 					}
 				}
-			} else {
-				g.EnterCNT = 0
 			}
 		}
 	}
-	if g.Level == 8 {
+	if g.Level > g.LevelCount() {
 		g.State = 5
 	}
 	return nil
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
+	canvas := g.Display.Canvas()
 	if g.State > 2 {
 		//draw background
 		backgroundX, backgroundY, backgroundW, backgroundH = AnimateBackground(backgroundX, backgroundY, backgroundW, backgroundH)
-		ebitenutil.DrawRect(screen, backgroundX, backgroundY, backgroundW, backgroundH, color.RGBA{221, 182, 242, 125})
-		if g.State != 5 {
-			//draw Lifes
-			g.DrawLifes(screen)
-			//draw Level
-			g.DrawLevel(screen)
-		}
-
-		//draw barrels
-		for _, b := range g.Barrels {
-			ebitenutil.DrawRect(screen, b.x, b.y, b.w, b.h, b.color)
+		ebitenutil.DrawRect(canvas, backgroundX, backgroundY, backgroundW, backgroundH, RoleColor(RoleBackground))
+		//draw world
+		world := g.Camera.World(g)
+		for _, e := range g.Entities {
+			e.Draw(world, g)
 		}
-		//draw obstacles
-		for _, o := range g.Obstacles {
-			ebitenutil.DrawRect(screen, o.x, o.y, o.w, o.h, o.color)
+		g.Particles.Draw(world)
+		// le joueur grandit en sortant d'un téléporteur
+		r := PlayerR * (1 - g.WarpTimer/WarpTicks)
+		if !DrawSprite(world, "player", g.AnimTick, g.PlayerX-r, g.PlayerY-r, 2*r, 2*r, 0, color.White) {
+			ebitenutil.DrawCircle(world, g.PlayerX, g.PlayerY, r, RoleColor(RolePlayer))
 		}
-		//draw Bouncers
-		for _, boun := range g.Bouncers {
-			ebitenutil.DrawRect(screen, boun.x, boun.y, boun.w, boun.h, boun.color)
+		g.Camera.Draw(canvas)
+		//draw keys
+		if g.State != 5 {
+			g.DrawKeys(canvas)
 		}
-		//draw particules
-		for _, p := range g.Particles {
-			ebitenutil.DrawRect(screen, p.x, p.y, 4, 4, p.color)
+	}
+	if g.State == 1 {
+		centerX, centerY := 320.0, 420.0 // position centrale (modifie selon ton UI)
+		spacing := 34.0                  // espacement horizontal entre points
+		baseR := 8.0                     // rayon de base
+		speed := 6.45                    // vitesse des pulsations
+
+		for i := 0; i < 3; i++ {
+			// phase décallée pour chaque point
+			phase := float64(i) * 0.9
+
+			// sin pour aller de -1..1, on transforme en 0..1 puis en scale 0.6..1.4
+			s := (math.Sin(g.TimeSaveAnimation*speed+phase) + 1.0) / 2.0
+			scale := 0.6 + 0.8*s // nombre entre 0.6 et 1.4
+
+			r := baseR * scale
+			x := centerX + (float64(i)-1.0)*spacing
+			// couleur : blanc, tu peux changer
+			ebitenutil.DrawCircle(canvas, x, centerY, r, color.RGBA{255, 255, 255, 255})
 		}
-		//draw teleporter lines
-		for _, b := range g.Barrels {
-			if b.Teleporter {
-				ebitenutil.DrawLine(screen, b.x, b.y, b.TeleporterX, b.TeleporterY, color.White)
-			}
+	}
+	g.Display.Present(screen)
+
+	// --- HUD ---
+	h := g.Display.HUD(screen)
+	if g.State > 2 {
+		if g.State != 5 {
+			//draw Lifes
+			g.DrawLifes(h)
+			//draw Level
+			g.DrawLevel(h)
+			//draw collectibles
+			g.DrawCollectibles(h)
 		}
 		//draw slowMotion
 		if g.slowMotionCooldown > 0 {
-			op := &text.DrawOptions{}
-			op.GeoM.Translate(float64(50), float64(200))
-			op.ColorScale.ScaleWithColor(color.RGBA{255, 255, 255, 255})
-			text.Draw(screen, "Slow Motion!", &text.GoTextFace{
-				Source: mplusFaceSource,
-				Size:   45,
-			}, op)
+			h.Text(T(MsgSlowMotion), 45, AnchorCenter, 0, -20, color.RGBA{255, 255, 255, 255})
 		}
-		//draw player
-		ebitenutil.DrawCircle(screen, g.PlayerX, g.PlayerY, PlayerR, color.RGBA{255, 255, 0, 255})
 		//draw version
-		ebitenutil.DebugPrintAt(screen, "version 1.4", 550, 460)
+		h.Text(T(MsgVersion, GameVersion), 14, AnchorBottomRight, -HUDMargin, -HUDMargin, color.White)
 		//draw timer
-		g.DrawTimer(screen)
+		g.DrawTimer(h)
 
 		//draw Restart button and top 5
 		if g.State == 5 {
-			g.DrawRestartButton(screen)
-			g.DrawTop5(screen)
+			g.DrawRestartButton(h)
+			g.DrawTop5(h)
+			g.DrawTop5Full(h)
+		}
+		if (g.State == 3 || g.State == 4) && input.TouchUsed {
+			DrawPauseButton(h)
+		}
+		if g.Paused {
+			g.DrawPause(h)
 		}
 		//draw change level animation
-		ebitenutil.DrawRect(screen, 0, 0, 640, 480, color.RGBA{0, 0, 0, uint8(g.Opacity)})
+		h.Rect(AnchorTopLeft, 0, 0, ScreenW, ScreenH, color.RGBA{0, 0, 0, uint8(g.Opacity)})
 	}
 	if g.State == 0 {
-		op := &text.DrawOptions{}
-		op.GeoM.Translate(float64(50), float64(200))
-		op.ColorScale.ScaleWithColor(color.RGBA{255, 255, 255, 255})
-		text.Draw(screen, fmt.Sprintf("UserName: %s", g.currentUserName), &text.GoTextFace{
-			Source: mplusFaceSource,
-			Size:   30,
-		}, op)
+		g.NameField.Draw(h, T(MsgUserName)+" ", 30, AnchorLeft, 50, -25, color.RGBA{255, 255, 255, 255})
+		g.Keyboard.Draw(h)
 	}
 	if g.State == 2 {
-		op := &text.DrawOptions{}
-		op.GeoM.Translate(100, 200) // même position verticale que UserName
-		op.ColorScale.ScaleWithColor(color.RGBA{255, 255, 255, 255})
-		text.Draw(screen, "Enter a code", &text.GoTextFace{
-			Source: mplusFaceSource,
-			Size:   30, // même taille que UserName
-		}, op)
-		op = &text.DrawOptions{}
-		op.GeoM.Translate(float64(50), float64(300))
-		op.ColorScale.ScaleWithColor(color.RGBA{255, 255, 255, 255})
-		text.Draw(screen, fmt.Sprintf("Code: %s", g.CurrentCode), &text.GoTextFace{
-			Source: mplusFaceSource,
-			Size:   30,
-		}, op)
+		// le clavier à l'écran occupe le bas : on remonte le champ et l'erreur
+		shift, errAnchor, errY := 0.0, AnchorBottomLeft, -HUDMargin-30.0
+		if g.Keyboard.Visible() {
+			shift, errAnchor, errY = -100, AnchorTopLeft, HUDMargin
+		}
+		h.Text(T(MsgEnterCode), 30, AnchorLeft, 100, shift-25, color.RGBA{255, 255, 255, 255})
+		g.CodeField.Draw(h, T(MsgCode)+" ", 30, AnchorLeft, 50, shift+75, color.RGBA{255, 255, 255, 255})
 		if g.TSICM > 0 {
-			op := &text.DrawOptions{}
-			op.GeoM.Translate(10, 420) // même position verticale que UserName
-			op.ColorScale.ScaleWithColor(color.RGBA{255, 255, 255, 255})
-			text.Draw(screen, "Invalid code", &text.GoTextFace{
-				Source: mplusFaceSource,
-				Size:   30, // même taille que UserName
-			}, op)
+			h.Text(T(MsgInvalidCode), 30, errAnchor, HUDMargin, errY, color.RGBA{255, 255, 255, 255})
 		}
+		g.Keyboard.Draw(h)
 	}
 	if g.State == 3 {
-		g.DrawTop5(screen)
+		g.DrawTop5(h)
 	}
 	if g.State == 1 {
-		op := &text.DrawOptions{}
-		op.GeoM.Translate(float64(50), float64(230))
-		op.ColorScale.ScaleWithColor(color.RGBA{255, 255, 255, 255})
-		text.Draw(screen, "Downloading UserName...", &text.GoTextFace{
-			Source: mplusFaceSource,
-			Size:   20,
-		}, op)
+		h.Text(T(MsgDownloading), 20, AnchorLeft, 50, 0, color.RGBA{255, 255, 255, 255})
 	}
-	if g.State == 1 {
-		centerX, centerY := 320.0, 420.0 // position centrale (modifie selon ton UI)
-		spacing := 34.0                  // espacement horizontal entre points
-		baseR := 8.0                     // rayon de base
-		speed := 6.45                    // vitesse des pulsations
+}
 
-		for i := 0; i < 3; i++ {
-			// phase décallée pour chaque point
-			phase := float64(i) * 0.9
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	w, h := g.LayoutF(float64(outsideWidth), float64(outsideHeight))
+	return int(w), int(h)
+}
 
-			// sin pour aller de -1..1, on transforme en 0..1 puis en scale 0.6..1.4
-			s := (math.Sin(g.TimeSaveAnimation*speed+phase) + 1.0) / 2.0
-			scale := 0.6 + 0.8*s // nombre entre 0.6 et 1.4
+func (g *Game) LayoutF(outsideWidth, outsideHeight float64) (float64, float64) {
+	return g.Display.Layout(outsideWidth, outsideHeight)
+}
 
-			r := baseR * scale
-			x := centerX + (float64(i)-1.0)*spacing
-			// couleur : blanc, tu peux changer
-			ebitenutil.DrawCircle(screen, x, centerY, r, color.RGBA{255, 255, 255, 255})
+func main() {
+	mouseAsTouch := flag.Bool("touch", false, "use the mouse as a finger to try the touch controls")
+	sandbox := flag.Bool("sandbox", false, "play the feature showcase levels, without ranking")
+	levelFile := flag.String("level", "", "play a single level from a JSON file, without ranking")
+	export := flag.String("export", "", "write the showcase levels as JSON files into this directory and exit")
+	flag.Parse()
+	if *export != "" {
+		if err := ExportSandbox(*export); err != nil {
+			log.Fatal(err)
 		}
+		return
 	}
-	if g.TUNE > 0 && g.ValidUserName != 0 {
-		var msg string
-		switch g.ValidUserName {
-		case 1:
-			msg = "Username is too long."
-		case 2:
-			msg = "Username must be alphanumeric."
-		case 3:
-			msg = "Username cannot be empty."
-		default:
-			msg = "Unknown username error."
+	var level *LevelData
+	if *levelFile != "" {
+		d, err := LoadLevelFromDisk(*levelFile)
+		if err != nil {
+			log.Fatal(err)
 		}
-		op := &text.DrawOptions{}
-		op.GeoM.Translate(10, 420)
-		op.ColorScale.ScaleWithColor(color.RGBA{255, 255, 255, 255})
-		text.Draw(screen, msg, &text.GoTextFace{
-			Source: mplusFaceSource,
-			Size:   20,
-		}, op)
+		level = &d
 	}
-}
-
-func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return 640, 480
-}
-
-func main() {
-	ebiten.SetWindowSize(640, 480)
+	ebiten.SetWindowSize(ScreenW, ScreenH)
 	ebiten.SetWindowTitle("Hello World")
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	if err := CheckCatalogs(); err != nil {
+		log.Fatal(err)
+	}
+	var err error
+	if atlas, err = LoadAtlas(SpriteFS(), "sprites/atlas.json"); err != nil {
+		log.Printf("sprites disabled: %v", err)
+	}
 	save, err := LoadFromDisk("save.json")
 	backgroundX = 319
 	backgroundY = 239
@@ -1084,49 +1160,28 @@ func main() {
 
 	g := &Game{
 		Save:                  save,
-		PlayerY:               240,
-		PlayerX:               160,
-		PlayerSpeed:           15,
 		Level:                 1,
 		OpacityPlusOrNegative: true,
 		PlayerLife:            3,
 		TimeBeforeLevelDown:   -67,
 		TimeSaveAnimation:     70,
+		NameField:             NewNameField(),
+		CodeField:             NewCodeField(),
+		MouseAsTouch:          *mouseAsTouch,
+		Sandbox:               *sandbox || level != nil,
+		LevelFile:             level,
+		PauseMenu:             NewPauseMenu(),
+		ControlsMenu:          NewControlsMenu(),
 
 		Top5Bestplayers: save.Top5, // récupérer le top5 depuis la sauvegarde
-		Barrels: []BarrelsS{
-			{
-				x:           50,
-				y:           215,
-				w:           100,
-				h:           50,
-				BarrelsDirY: true,
-				Moved:       false,
-				fragile:     false,
-				Teleporter:  false,
-				Magic:       false,
-				TeleporterX: 0,
-				TeleporterY: 0,
-				CoolDown:    100,
-				color:       color.RGBA{139, 69, 19, 255},
-			},
-			{
-				x:           490,
-				y:           215,
-				w:           100,
-				h:           50,
-				BarrelsDirY: true,
-				Moved:       false,
-				fragile:     false,
-				Teleporter:  false,
-				Magic:       false,
-				TeleporterX: 0,
-				TeleporterY: 0,
-				CoolDown:    100,
-				color:       color.RGBA{139, 69, 19, 255},
-			},
-		},
 	}
+	g.StartLevel()
+	g.Display.IntegerScaling = save.Settings.IntegerScaling
+	SetTheme(save.Settings.Theme)
+	SetLanguage(save.Settings.Language)
+	SetBindings(save.Settings.Controls)
+	SetInputBuffer(save.Settings.InputBuffer)
+	ebiten.SetFullscreen(save.Settings.Fullscreen)
 
 	f, err := os.Open("mixkit-infected-vibes-157.mp3")
 	if err != nil {
@@ -1162,6 +1217,7 @@ func main() {
 	g.crackSound, _ = LoadSound("mixkit-bone-breaking-with-echo-2937.wav")
 	g.hitSound, _ = LoadSound("mixkit-cowbell-sharp-hit-1743.wav")
 	g.slowMotionSound, _ = LoadSound("mixkit-fast-swipe-zoom-2627.wav")
+	g.CollectSound, _ = LoadSound("mixkit-fantasy-game-success-notification-270.wav")
 	g.NameConfirmSound, _ = LoadSound("mixkit-sci-fi-confirmation-914.mp3")
 	if g.NameConfirmSound == nil {
 		log.Println("Attention: NameConfirmSound non trouvé...Utilisation d'un autre son pour le remplacer.")
@@ -1176,6 +1232,7 @@ func main() {
 	g.barrelShootSound.SetVolume(0.75)
 	g.RaceStartSound.SetVolume(0.75)
 	g.crackSound.SetVolume(1)
+	g.CollectSound.SetVolume(0.75)
 
 	if err := ebiten.RunGame(g); err != nil {
 		log.Fatal(err)
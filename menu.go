@@ -0,0 +1,257 @@
+package main
+
+import (
+	"image/color"
+	"slices"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	MenuItemW   = 420
+	MenuItemGap = 8
+)
+
+// Menu est une liste d'entrées centrée à l'écran, qu'on parcourt avec
+// haut/bas et qu'on valide avec Confirm, à la souris ou au doigt.
+type Menu struct {
+	Items    []string // identifiants des messages
+	ItemH    float64
+	Selected int
+	lastX    float64
+	lastY    float64
+}
+
+// ItemRect renvoie le rectangle de l'entrée i dans le canvas.
+func (m *Menu) ItemRect(i int) (float64, float64, float64, float64) {
+	total := float64(len(m.Items))*(m.ItemH+MenuItemGap) - MenuItemGap
+	return ScreenW/2 - MenuItemW/2, ScreenH/2 - total/2 + float64(i)*(m.ItemH+MenuItemGap), MenuItemW, m.ItemH
+}
+
+// Update déplace la sélection et renvoie l'entrée choisie, -1 si aucune.
+// (x, y) est la souris dans le canvas.
+func (m *Menu) Update(x, y float64) int {
+	n := len(m.Items)
+	if IsActionJustPressed(ActionUp) {
+		m.Selected = (m.Selected + n - 1) % n
+	}
+	if IsActionJustPressed(ActionDown) {
+		m.Selected = (m.Selected + 1) % n
+	}
+	// la souris ne prend la sélection que si elle bouge, pour ne pas gêner
+	// le clavier quand elle reste posée sur une entrée
+	moved := x != m.lastX || y != m.lastY
+	m.lastX, m.lastY = x, y
+	for i := range m.Items {
+		rx, ry, rw, rh := m.ItemRect(i)
+		if !Within(x, y, rx, ry, rw, rh) {
+			continue
+		}
+		if moved {
+			m.Selected = i
+		}
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || input.Tapped(rx, ry, rw, rh) {
+			m.Selected = i
+			return i
+		}
+	}
+	if IsActionJustPressed(ActionConfirm) {
+		return m.Selected
+	}
+	return -1
+}
+
+// Draw dessine les entrées avec leur texte, et values à droite s'il y en a.
+func (m *Menu) Draw(h HUD, values []string) {
+	size := m.ItemH / 2
+	for i, id := range m.Items {
+		x, y, w, ht := m.ItemRect(i)
+		clr := color.RGBA{60, 60, 60, 230}
+		if i == m.Selected {
+			clr = color.RGBA{0, 255, 0, 255}
+		}
+		h.Rect(AnchorTopLeft, x, y, w, ht, clr)
+		h.Text(T(id), size, AnchorLeft, x+12, y+ht/2-ScreenH/2, color.White)
+		if i < len(values) && values[i] != "" {
+			h.Text(values[i], size*0.7, AnchorRight, x+w-12-ScreenW, y+ht/2-ScreenH/2, color.White)
+		}
+	}
+}
+
+// --- PAUSE ---
+
+// entrées du menu pause
+const (
+	PauseResume = iota
+	PauseRestart
+	PauseControls
+	PauseLanguage
+)
+
+func NewPauseMenu() Menu {
+	return Menu{Items: []string{MsgResume, MsgRestart, MsgControls, MsgLanguage}, ItemH: 48}
+}
+
+// NewControlsMenu crée l'écran des contrôles : une entrée par action, puis
+// l'attente des appuis, la remise à zéro et le retour.
+func NewControlsMenu() Menu {
+	m := Menu{ItemH: 28}
+	for a := range ActionCount {
+		m.Items = append(m.Items, actionMessages[a])
+	}
+	m.Items = append(m.Items, MsgInputBuffer, MsgResetControls, MsgBack)
+	return m
+}
+
+var actionMessages = [ActionCount]string{MsgActionLaunch, MsgActionPause, MsgActionRestart, MsgActionUp, MsgActionDown, MsgActionLeft, MsgActionRight, MsgActionConfirm, MsgActionBack}
+
+// Pause fige la partie et ouvre le menu pause.
+func (g *Game) Pause() {
+	g.Paused = true
+	g.pausedAt = time.Now()
+	g.PauseMenu.Selected = PauseResume
+	g.ControlsOpen = false
+}
+
+// Resume reprend la partie sans compter le temps passé en pause.
+func (g *Game) Resume() {
+	g.Paused = false
+	input.Reset()
+	if !g.StartTime.IsZero() {
+		g.StartTime = g.StartTime.Add(time.Since(g.pausedAt))
+	}
+}
+
+// RunTime renvoie le temps de course, arrêté pendant la pause.
+func (g *Game) RunTime() time.Duration {
+	if g.Paused {
+		return g.pausedAt.Sub(g.StartTime)
+	}
+	return time.Since(g.StartTime)
+}
+
+// UpdatePause gère le menu pause et l'écran des contrôles.
+func (g *Game) UpdatePause() {
+	if g.ControlsOpen {
+		g.UpdateControls()
+		return
+	}
+	if IsActionJustPressed(ActionPause) || IsActionJustPressed(ActionBack) {
+		g.Resume()
+		return
+	}
+	if IsActionJustPressed(ActionRestart) {
+		g.RestartRun()
+		return
+	}
+	switch g.PauseMenu.Update(g.Display.CursorPosition()) {
+	case PauseResume:
+		g.Resume()
+	case PauseRestart:
+		g.RestartRun()
+	case PauseControls:
+		g.ControlsOpen = true
+		g.ControlsMenu.Selected = 0
+	case PauseLanguage:
+		g.Save.Settings.Language = NextLanguage()
+		SaveToDisk(g.Save, "save.json")
+	}
+}
+
+// UpdateControls gère l'écran des contrôles. Valider une action attend la
+// prochaine touche ou le prochain bouton, qui remplace ceux de l'action ;
+// Échap ou un toucher de l'écran annule.
+func (g *Game) UpdateControls() {
+	m := &g.ControlsMenu
+	if g.rebinding {
+		a := Action(m.Selected)
+		// un écran tactile n'a ni touche ni bouton à lier
+		if input.JustTouched() {
+			g.rebinding = false
+			return
+		}
+		changed := false
+		g.keys = inpututil.AppendJustPressedKeys(g.keys[:0])
+		for _, k := range g.keys {
+			if k == ebiten.KeyEscape {
+				g.rebinding = false
+				return
+			}
+			BindKey(a, k)
+			changed = true
+		}
+		for _, id := range Gamepads() {
+			g.buttons = inpututil.AppendJustPressedStandardGamepadButtons(id, g.buttons[:0])
+			for _, btn := range g.buttons {
+				BindButton(a, btn)
+				changed = true
+			}
+		}
+		if changed {
+			g.rebinding = false
+			input.Hold(a)
+			g.Save.Settings.Controls = SavedBindings()
+			SaveToDisk(g.Save, "save.json")
+		}
+		return
+	}
+	if IsActionJustPressed(ActionBack) {
+		g.ControlsOpen = false
+		return
+	}
+	switch i := m.Update(g.Display.CursorPosition()); {
+	case i < 0:
+	case i < int(ActionCount):
+		g.rebinding = true
+	case m.Items[i] == MsgInputBuffer:
+		next := BufferOptions[(slices.Index(BufferOptions, g.bufferSetting())+1)%len(BufferOptions)]
+		g.Save.Settings.InputBuffer = next
+		SetInputBuffer(next)
+		SaveToDisk(g.Save, "save.json")
+	case m.Items[i] == MsgResetControls:
+		ResetBindings()
+		g.Save.Settings.Controls = nil
+		g.Save.Settings.InputBuffer = 0
+		SetInputBuffer(0)
+		SaveToDisk(g.Save, "save.json")
+	default:
+		g.ControlsOpen = false
+	}
+}
+
+// bufferSetting renvoie l'attente choisie, DefaultInputBuffer si elle ne
+// l'a jamais été.
+func (g *Game) bufferSetting() int {
+	if g.Save.Settings.InputBuffer == 0 {
+		return DefaultInputBuffer
+	}
+	return g.Save.Settings.InputBuffer
+}
+
+// DrawPause dessine le menu pause ou l'écran des contrôles par dessus le jeu.
+func (g *Game) DrawPause(h HUD) {
+	h.Rect(AnchorTopLeft, 0, 0, ScreenW, ScreenH, color.RGBA{0, 0, 0, 160})
+	if !g.ControlsOpen {
+		h.Text(T(MsgPaused), 45, AnchorTop, 0, 2*HUDLine, color.White)
+		values := make([]string, len(g.PauseMenu.Items))
+		values[PauseLanguage] = T(MsgLanguageName)
+		g.PauseMenu.Draw(h, values)
+		return
+	}
+	values := make([]string, len(g.ControlsMenu.Items))
+	for a := range ActionCount {
+		values[a] = BindingLabel(a)
+	}
+	if b := g.bufferSetting(); b < 0 {
+		values[ActionCount] = T(MsgOff)
+	} else {
+		values[ActionCount] = TN(MsgTicks, b, b)
+	}
+	if g.rebinding {
+		values[g.ControlsMenu.Selected] = T(MsgPressKey)
+	}
+	h.Text(T(MsgControls), 18, AnchorTop, 0, HUDMargin/2, color.White)
+	g.ControlsMenu.Draw(h, values)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Obstacle coûte une vie quand le joueur le touche.
+type Obstacle struct {
+	X, Y, W, H float64
+	Path       *Path
+}
+
+func NewObstacle(x, y float64) *Obstacle {
+	return &Obstacle{X: x, Y: y, W: 50, H: 50}
+}
+
+func (o *Obstacle) WithPath(p *Path) *Obstacle {
+	o.Path = p
+	return o
+}
+
+func (o *Obstacle) Update(g *Game) {
+	if o.Path != nil {
+		o.X, o.Y = o.Path.Step(o.X, o.Y, g.TimeScale())
+	}
+}
+
+func (o *Obstacle) CollidePlayer(g *Game) {
+	if CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, o.X, o.Y, o.W, o.H) {
+		g.HitHazard()
+	}
+}
+
+// HitHazard fait perdre une vie au joueur touché par un danger et le renvoie
+// au départ du niveau ou au dernier checkpoint.
+func (g *Game) HitHazard() {
+	if g.ChangeLevelAnimation {
+		return
+	}
+	g.Respawn()
+	g.Shake(0.5)
+	g.PlayerLife--
+	g.loseSound2.Rewind()
+	g.loseSound2.Play()
+	g.hitSound.Rewind()
+	g.hitSound.Play()
+}
+
+func (o *Obstacle) SweepRect(g *Game) (float64, float64, float64, float64, bool) {
+	return o.X, o.Y, o.W, o.H, true
+}
+
+func (o *Obstacle) Draw(screen *ebiten.Image, g *Game) {
+	clr := RoleColor(RoleObstacle)
+	if !DrawSprite(screen, "obstacle", g.AnimTick, o.X, o.Y, o.W, o.H, 0, clr) {
+		ebitenutil.DrawRect(screen, o.X, o.Y, o.W, o.H, clr)
+	}
+	DrawCross(screen, o.X, o.Y, o.W, o.H, RoleColor(RoleCue))
+}
+
+func (o *Obstacle) Serialize() EntityData {
+	return serializeEntity("obstacle", o)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const (
+	// MaxPan limite le panoramique pour qu'un son au bord de l'écran
+	// reste audible dans les deux oreilles.
+	MaxPan = 0.8
+)
+
+// PanStream enveloppe un flux PCM 16 bits stéréo et applique un
+// panoramique gauche/droite. Le pan va de -1 (gauche) à 1 (droite).
+type PanStream struct {
+	src io.ReadSeeker
+	pan atomic.Uint64
+}
+
+// soundPans retrouve le PanStream d'un player créé par LoadSound.
+var soundPans = map[*audio.Player]*PanStream{}
+
+func NewPanStream(src io.ReadSeeker) *PanStream {
+	return &PanStream{src: src}
+}
+
+func (s *PanStream) SetPan(pan float64) {
+	s.pan.Store(math.Float64bits(math.Max(-1, math.Min(1, pan))))
+}
+
+func (s *PanStream) Pan() float64 {
+	return math.Float64frombits(s.pan.Load())
+}
+
+// gains utilise une loi de balance : le centre garde le volume d'origine.
+func (s *PanStream) gains() (float64, float64) {
+	pan := s.Pan()
+	if pan < 0 {
+		return 1, 1 + pan
+	}
+	return 1 - pan, 1
+}
+
+func (s *PanStream) Read(p []byte) (int, error) {
+	// On ne traite que des trames complètes (2 canaux x 16 bits).
+	p = p[:len(p)/4*4]
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n, err := s.src.Read(p)
+	if r := n % 4; r != 0 && err == nil {
+		m, err2 := io.ReadFull(s.src, p[n:n+4-r])
+		n += m
+		if err2 == io.ErrUnexpectedEOF {
+			err2 = io.EOF
+		}
+		err = err2
+	}
+
+	left, right := s.gains()
+	if left == 1 && right == 1 {
+		return n, err
+	}
+	for i := 0; i+4 <= n; i += 4 {
+		l := float64(int16(binary.LittleEndian.Uint16(p[i:])))
+		r := float64(int16(binary.LittleEndian.Uint16(p[i+2:])))
+		binary.LittleEndian.PutUint16(p[i:], uint16(int16(l*left)))
+		binary.LittleEndian.PutUint16(p[i+2:], uint16(int16(r*right)))
+	}
+	return n, err
+}
+
+func (s *PanStream) Seek(offset int64, whence int) (int64, error) {
+	return s.src.Seek(offset, whence)
+}
+
+// PanForX convertit une position x à l'écran (0..640) en pan.
+func PanForX(x float64) float64 {
+	return math.Max(-1, math.Min(1, (x-320)/320)) * MaxPan
+}
+
+// PlayAt rejoue un son en le plaçant dans l'espace stéréo selon x, une
+// position du niveau placée à l'écran par la caméra.
+func (g *Game) PlayAt(p *audio.Player, x float64) {
+	if ps, ok := soundPans[p]; ok {
+		ps.SetPan(PanForX(g.Camera.ScreenX(x)))
+	}
+	p.Rewind()
+	p.Play()
+}
+
+// NewPannedPlayer crée un player dont le pan peut être réglé avec PlayAt.
+func NewPannedPlayer(src io.ReadSeeker) (*audio.Player, error) {
+	ps := NewPanStream(src)
+	p, err := audioContext.NewPlayer(ps)
+	if err != nil {
+		return nil, err
+	}
+	soundPans[p] = ps
+	return p, nil
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// MaxParticles est la taille du pool : au-delà, les nouvelles particules
+// sont ignorées.
+const MaxParticles = 2048
+
+type ParticleShape int
+
+const (
+	ParticleSquare ParticleShape = iota
+	ParticleCircle
+	ParticleStreak // trait dans le sens de la vitesse
+)
+
+// Emitter décrit une gerbe de particules. Les angles sont en radians,
+// 0 = vers la droite ; Spread est l'ouverture totale autour de Angle.
+type Emitter struct {
+	Count              int
+	Angle, Spread      float64
+	Ring               bool    // directions régulières au lieu d'aléatoires
+	Radius             float64 // distance de départ au point d'émission
+	Inward             bool    // les particules vont vers le point d'émission
+	Swirl              float64 // part de vitesse tangentielle (tourbillon)
+	MinSpeed, MaxSpeed float64
+	Gravity            float64
+	MinLife, MaxLife   int
+	Colors             []color.RGBA // dégradé du début à la fin de la vie
+	Size, EndSize      float64
+	Fade               bool
+	Shape              ParticleShape
+}
+
+type Particle struct {
+	x, y    float64
+	vx, vy  float64
+	life    float64 // en ticks
+	maxLife float64
+	emitter *Emitter
+}
+
+// ParticlePool garde les particules vivantes au début de items, sans
+// réallouer à chaque frame.
+type ParticlePool struct {
+	items []Particle
+	n     int
+}
+
+func (p *ParticlePool) Clear() {
+	p.n = 0
+}
+
+func (p *ParticlePool) Len() int {
+	return p.n
+}
+
+func (p *ParticlePool) add(part Particle) {
+	if p.items == nil {
+		p.items = make([]Particle, MaxParticles)
+	}
+	if p.n == len(p.items) {
+		return
+	}
+	p.items[p.n] = part
+	p.n++
+}
+
+// Update avance les particules de dt ticks (moins de 1 en slow motion).
+func (p *ParticlePool) Update(dt float64) {
+	for i := 0; i < p.n; i++ {
+		part := &p.items[i]
+		part.x += part.vx * dt
+		part.y += part.vy * dt
+		part.vy += part.emitter.Gravity * dt
+		part.life -= dt
+		if part.life <= 0 {
+			// la dernière particule prend la place de la morte
+			p.n--
+			p.items[i] = p.items[p.n]
+			i--
+		}
+	}
+}
+
+func (p *ParticlePool) Draw(screen *ebiten.Image) {
+	for _, part := range p.items[:p.n] {
+		e := part.emitter
+		t := 1 - part.life/part.maxLife
+		clr := gradient(e.Colors, t)
+		if e.Fade {
+			clr = scaleColor(clr, 1-t)
+		}
+		size := e.Size + (e.EndSize-e.Size)*t
+		switch e.Shape {
+		case ParticleCircle:
+			vector.DrawFilledCircle(screen, float32(part.x), float32(part.y), float32(size/2), clr, true)
+		case ParticleStreak:
+			vector.StrokeLine(screen, float32(part.x-part.vx*2), float32(part.y-part.vy*2), float32(part.x), float32(part.y), float32(size/2), clr, true)
+		default:
+			ebitenutil.DrawRect(screen, part.x, part.y, size, size, clr)
+		}
+	}
+}
+
+// Emit lance une gerbe de e en (x, y), tournée de dir radians.
+func (g *Game) Emit(e *Emitter, x, y, dir float64) {
+	for i := 0; i < e.Count; i++ {
+		a := dir + e.Angle + (rand.Float64()-0.5)*e.Spread
+		if e.Ring {
+			a = dir + e.Angle - e.Spread/2 + e.Spread*float64(i)/float64(e.Count)
+		}
+		speed := e.MinSpeed + rand.Float64()*(e.MaxSpeed-e.MinSpeed)
+		radial := 1.0
+		if e.Inward {
+			radial = -1
+		}
+		life := e.MinLife
+		if e.MaxLife > e.MinLife {
+			life += rand.Intn(e.MaxLife - e.MinLife)
+		}
+		cos, sin := math.Cos(a), math.Sin(a)
+		g.Particles.add(Particle{
+			x:       x + cos*e.Radius,
+			y:       y + sin*e.Radius,
+			vx:      (cos*radial - sin*e.Swirl) * speed,
+			vy:      (sin*radial + cos*e.Swirl) * speed,
+			life:    float64(life),
+			maxLife: float64(life),
+			emitter: e,
+		})
+	}
+}
+
+func gradient(colors []color.RGBA, t float64) color.RGBA {
+	if len(colors) == 0 {
+		return color.RGBA{255, 255, 255, 255}
+	}
+	if len(colors) == 1 || t <= 0 {
+		return colors[0]
+	}
+	if t >= 1 {
+		return colors[len(colors)-1]
+	}
+	f := t * float64(len(colors)-1)
+	i := int(f)
+	a, b := colors[i], colors[i+1]
+	k := f - float64(i)
+	lerp := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*k)
+	}
+	return color.RGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), lerp(a.A, b.A)}
+}
+
+// scaleColor rend une couleur (prémultipliée) plus transparente.
+func scaleColor(c color.RGBA, k float64) color.RGBA {
+	return color.RGBA{uint8(float64(c.R) * k), uint8(float64(c.G) * k), uint8(float64(c.B) * k), uint8(float64(c.A) * k)}
+}
+
+// --- ÉMETTEURS ---
+
+var (
+	ExplosionEmitter = Emitter{
+		Count: 20, Spread: 2 * math.Pi, MaxSpeed: 2.5, Gravity: 0.1,
+		MinLife: 25, MaxLife: 35,
+		Colors: []color.RGBA{{169, 99, 29, 255}, {90, 50, 15, 255}}, // éclats de bois
+		Size:   4, EndSize: 3,
+	}
+	BounceEmitter = Emitter{
+		Count: 12, Spread: 1.6, MinSpeed: 2, MaxSpeed: 5, Gravity: 0.1,
+		MinLife: 15, MaxLife: 25,
+		Colors: []color.RGBA{{120, 180, 235, 255}}, // bleu clair
+		Size:   4, Fade: true, Shape: ParticleStreak,
+	}
+	CheckpointEmitter = Emitter{
+		Count: 24, Spread: 2 * math.Pi, Ring: true, MinSpeed: 3, MaxSpeed: 3, Gravity: 0.1,
+		MinLife: 30, MaxLife: 30,
+		Colors: []color.RGBA{{255, 255, 255, 255}, {60, 220, 120, 255}},
+		Size:   4, Shape: ParticleCircle,
+	}
+	WarpInEmitter = Emitter{
+		Count: 20, Spread: 2 * math.Pi, Ring: true, Radius: 40, Inward: true, Swirl: 1,
+		MinSpeed: 2, MaxSpeed: 4, MinLife: 15, MaxLife: 23,
+		Colors: []color.RGBA{{80, 220, 255, 255}, {255, 255, 255, 255}},
+		Size:   4, EndSize: 1, Shape: ParticleCircle,
+	}
+	WarpOutEmitter = Emitter{
+		Count: 20, Spread: 2 * math.Pi, Ring: true, Swirl: 1,
+		MinSpeed: 2, MaxSpeed: 4, MinLife: 15, MaxLife: 23,
+		Colors: []color.RGBA{{255, 255, 255, 255}, {80, 220, 255, 255}},
+		Size:   2, EndSize: 5, Fade: true, Shape: ParticleCircle,
+	}
+	LandingEmitter = Emitter{
+		Count: 8, Angle: -math.Pi / 2, Spread: 2.4, MinSpeed: 0.5, MaxSpeed: 1.5, Gravity: 0.05,
+		MinLife: 12, MaxLife: 20,
+		Colors: []color.RGBA{{200, 190, 170, 200}},
+		Size:   5, EndSize: 8, Fade: true, Shape: ParticleCircle,
+	}
+	SlowMotionEmitter = Emitter{
+		Count: 16, Spread: 2 * math.Pi, Radius: 35, Swirl: 0.6, MinSpeed: 0.5, MaxSpeed: 1.5,
+		MinLife: 30, MaxLife: 45,
+		Colors: []color.RGBA{{138, 43, 226, 255}, {255, 255, 255, 255}},
+		Size:   3, Fade: true, Shape: ParticleCircle,
+	}
+	LevelClearEmitter = Emitter{
+		Count: 60, Angle: -math.Pi / 2, Spread: 2, MinSpeed: 3, MaxSpeed: 8, Gravity: 0.15,
+		MinLife: 40, MaxLife: 70,
+		Colors: []color.RGBA{{255, 215, 0, 255}, {255, 80, 160, 255}, {80, 200, 255, 255}}, // confettis
+		Size:   5, Fade: true,
+	}
+)
@@ -0,0 +1,134 @@
+package main
+
+import "math"
+
+type PathKind int
+
+const (
+	PathPingPong  PathKind = iota // aller-retour horizontal ou vertical entre Min et Max
+	PathOrbit                     // orbite circulaire autour de (CX, CY)
+	PathWaypoints                 // boucle de points, vitesse et easing par segment
+)
+
+type Easing int
+
+const (
+	EaseLinear Easing = iota
+	EaseIn
+	EaseOut
+	EaseInOut
+)
+
+func (e Easing) Apply(t float64) float64 {
+	switch e {
+	case EaseIn:
+		return t * t
+	case EaseOut:
+		return 1 - (1-t)*(1-t)
+	case EaseInOut:
+		return (1 - math.Cos(t*math.Pi)) / 2
+	default:
+		return t
+	}
+}
+
+// Waypoint est un point du chemin. Speed et Ease s'appliquent au segment
+// qui part de ce point vers le suivant.
+type Waypoint struct {
+	X, Y  float64
+	Speed float64
+	Ease  Easing
+}
+
+// Path décrit le déplacement d'un objet. Les positions sont celles du coin
+// haut gauche de l'objet, comme x et y dans les structs des objets.
+type Path struct {
+	Kind  PathKind
+	Speed float64 // px par tick (PingPong) ou radians par tick (Orbit)
+
+	// PathPingPong
+	Horizontal bool
+	Min, Max   float64
+	Forward    bool // vers Max
+
+	// PathOrbit
+	CX, CY, Radius, Phase float64
+
+	// PathWaypoints
+	Points []Waypoint
+	Seg    int     // segment en cours
+	T      float64 // avancée sur ce segment, de 0 à 1
+}
+
+func NewPingPong(horizontal bool, min, max, speed float64, forward bool) *Path {
+	return &Path{Kind: PathPingPong, Horizontal: horizontal, Min: min, Max: max, Speed: speed, Forward: forward}
+}
+
+func NewOrbit(cx, cy, radius, speed, phase float64) *Path {
+	return &Path{Kind: PathOrbit, CX: cx, CY: cy, Radius: radius, Speed: speed, Phase: phase}
+}
+
+func NewWaypoints(points ...Waypoint) *Path {
+	return &Path{Kind: PathWaypoints, Points: points}
+}
+
+// Step avance le chemin de dt ticks (1 en temps normal, moins en slow motion)
+// et renvoie la nouvelle position de l'objet.
+func (p *Path) Step(x, y, dt float64) (float64, float64) {
+	switch p.Kind {
+	case PathPingPong:
+		pos := &y
+		if p.Horizontal {
+			pos = &x
+		}
+		if *pos > p.Max {
+			p.Forward = false
+		}
+		if *pos < p.Min {
+			p.Forward = true
+		}
+		if p.Forward {
+			*pos += p.Speed * dt
+		} else {
+			*pos -= p.Speed * dt
+		}
+		return x, y
+
+	case PathOrbit:
+		p.Phase += p.Speed * dt
+		return p.CX + math.Cos(p.Phase)*p.Radius, p.CY + math.Sin(p.Phase)*p.Radius
+
+	case PathWaypoints:
+		if len(p.Points) < 2 {
+			return x, y
+		}
+		// on avance en distance, le reste passe au segment suivant
+		dist := p.Points[p.Seg].Speed * dt
+		for n := 0; dist > 0 && n <= len(p.Points); n++ {
+			a, b := p.Points[p.Seg], p.Points[(p.Seg+1)%len(p.Points)]
+			length := math.Hypot(b.X-a.X, b.Y-a.Y)
+			if length == 0 {
+				p.Seg = (p.Seg + 1) % len(p.Points)
+				p.T = 0
+				continue
+			}
+			left := (1 - p.T) * length
+			if dist < left {
+				p.T += dist / length
+				break
+			}
+			dist -= left
+			// la vitesse du segment suivant s'applique au reste
+			next := p.Points[(p.Seg+1)%len(p.Points)]
+			if a.Speed > 0 {
+				dist *= next.Speed / a.Speed
+			}
+			p.Seg = (p.Seg + 1) % len(p.Points)
+			p.T = 0
+		}
+		a, b := p.Points[p.Seg], p.Points[(p.Seg+1)%len(p.Points)]
+		e := a.Ease.Apply(p.T)
+		return a.X + (b.X-a.X)*e, a.Y + (b.Y-a.Y)*e
+	}
+	return x, y
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"path/filepath"
+)
+
+// LastSandboxLevel est le nombre de niveaux du bac à sable.
+const LastSandboxLevel = 12
+
+// ExportSandbox écrit chaque niveau du bac à sable dans dir, au format lu
+// par l'option -level.
+func ExportSandbox(dir string) error {
+	g := &Game{Sandbox: true}
+	for L := 1; L <= LastSandboxLevel; L++ {
+		g.Generate_Level(L)
+		name := filepath.Join(dir, fmt.Sprintf("sandbox-%d.json", L))
+		if err := SaveLevelToDisk(g.SaveLevel(), name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LevelCount renvoie le nombre de niveaux de la partie en cours.
+func (g *Game) LevelCount() int {
+	if g.LevelFile != nil {
+		return 1
+	}
+	if g.Sandbox {
+		return LastSandboxLevel
+	}
+	return LastLevel
+}
+
+// sandboxLevel crée le niveau L du bac à sable (-sandbox), qui montre
+// chaque type d'objet dans un niveau à part.
+// Ces niveaux ne font pas partie de la course : leurs temps ne sont pas
+// classés et leurs étoiles ne sont pas enregistrées.
+func (g *Game) sandboxLevel(L int) {
+	if g.LevelFile != nil {
+		// déjà vérifié par LoadLevelFromDisk au lancement
+		if err := g.LoadLevel(*g.LevelFile); err != nil {
+			log.Printf("level: %v", err)
+		}
+		return
+	}
+	switch L {
+	case 1: // canon qui tourne
+		cannon := NewBarrel(270, 215)
+		cannon.RotSpeed = 0.03
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			cannon,
+			NewGoalBarrel(490, 50),
+			NewObstacle(420, 300),
+			NewStar(450, 150),
+		}
+	case 2: // tir automatique
+		up := NewBarrel(270, 215)
+		up.Angle = -math.Pi / 2
+		up.AutoFire = 40
+		right := NewBarrel(270, 50)
+		right.AutoFire = 40
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			up,
+			right,
+			NewGoalBarrel(490, 50),
+			NewObstacle(420, 80).WithPath(vertical(true)),
+		}
+	case 3: // chemins
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			NewBarrel(270, 215),
+			NewGoalBarrel(490, 215),
+			NewCoin(200, 240),
+			NewObstacle(280, 215).WithPath(NewOrbit(240, 215, 40, 0.05, 0)),
+			NewBouncer(410, 100).WithPath(NewWaypoints(
+				Waypoint{410, 100, 4, EaseInOut},
+				Waypoint{410, 330, 4, EaseInOut},
+			)),
+		}
+	case 4: // bouncers inclinés
+		up := NewBouncer(240, 215)
+		up.Angle = math.Pi / 4
+		right := NewBouncer(180, 60)
+		right.Angle = -3 * math.Pi / 4
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			NewGoalBarrel(490, 110),
+			up,
+			right,
+		}
+	case 5: // pièces
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			NewGoalBarrel(490, 215),
+			NewCoin(260, 240),
+			NewCoin(400, 240),
+		}
+	case 6: // clés, interrupteurs et portes
+		gold := color.RGBA{255, 200, 0, 255}
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			NewKey(215, 240, "gold", gold),
+			NewTimedSwitch(205, 262, 30, 12, "a", 120),
+			NewLock(245, 150, 16, 180, "gold", gold),
+			NewBarrel(270, 215),
+			NewGate(420, 150, 16, 180, "a"),
+			NewGoalBarrel(490, 215),
+		}
+	case 7: // ennemis
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			NewBarrel(270, 215),
+			NewGoalBarrel(490, 215),
+			NewPatroller(215, 150, NewPingPong(false, 150, 330, 3, true)),
+			NewTurret(400, 60, 90, 4),
+			NewChaser(560, 420, 1.2, 0.04),
+		}
+	case 8: // checkpoint
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			NewCheckpointBarrel(270, 215),
+			NewGoalBarrel(490, 215),
+			NewPatroller(215, 150, NewPingPong(false, 150, 330, 3, true)),
+			NewObstacle(420, 80).WithPath(vertical(true)),
+		}
+	case 9: // téléporteurs liés
+		in, out := NewTeleporterPair("p", 300, 215, 450, 380, false)
+		out.WithExit(-math.Pi / 2)
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			in,
+			out,
+			NewGoalBarrel(450, 50),
+			NewObstacle(480, 220).WithPath(NewPingPong(true, 380, 560, 2, true)),
+		}
+	case 10: // barils fragiles
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			NewFragileBarrel(270, 215).WithBreak(BreakFall).WithRespawn(3),
+			NewFragileBarrel(270, 380).WithBreak(BreakNothing),
+			NewGoalBarrel(490, 215),
+			NewObstacle(420, 80).WithPath(vertical(true)),
+		}
+	case 11: // champs de force
+		g.Entities = []Entity{
+			NewGravityZone(250, 100, 200, 380, 0.5),
+			NewWindZone(250, 0, 390, 100, 0, 0.3),
+			NewDragZone(460, 200, 180, 160, 0.05),
+			NewBarrel(50, 215),
+			NewGoalBarrel(490, 270),
+		}
+	case 12: // niveau plus large que l'écran
+		g.LevelW = 1600
+		g.Entities = []Entity{
+			NewBarrel(50, 215),
+			NewObstacle(300, 80).WithPath(vertical(true)),
+			NewBarrel(450, 215),
+			NewObstacle(700, 80).WithPath(vertical(false)),
+			NewBarrel(850, 215).WithPath(NewPingPong(false, 120, 330, 1.5, true)),
+			NewObstacle(1100, 80).WithPath(vertical(true)),
+			NewCheckpointBarrel(1250, 215),
+			NewGoalBarrel(1450, 215),
+		}
+	}
+}
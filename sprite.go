@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/png"
+	"io/fs"
+	"os"
+	"path"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+//go:embed sprites
+var spriteFiles embed.FS
+
+// SpriteFS renvoie le dossier sprites posé à côté du jeu s'il existe, pour
+// changer les sprites sans recompiler, sinon la copie intégrée au binaire.
+// Un atlas sur le disque illisible donne les rectangles de couleur.
+func SpriteFS() fs.FS {
+	if _, err := os.Stat("sprites/atlas.json"); err == nil {
+		return os.DirFS(".")
+	}
+	return spriteFiles
+}
+
+// SpriteAtlas contient les animations découpées dans une seule texture.
+// Quand atlas est nil, les objets sont dessinés en rectangles de couleur.
+type SpriteAtlas struct {
+	Image      string                      `json:"image"`
+	Animations map[string]*SpriteAnimation `json:"animations"`
+}
+
+type SpriteFrame struct {
+	X, Y, W, H int
+}
+
+// SpriteAnimation fait défiler Frames en boucle, une image toutes les
+// FrameTicks ticks.
+type SpriteAnimation struct {
+	FrameTicks int           `json:"frameTicks"`
+	Frames     []SpriteFrame `json:"frames"`
+	images     []*ebiten.Image
+}
+
+var atlas *SpriteAtlas
+
+// LoadAtlas lit la description JSON de l'atlas et la texture qu'elle nomme.
+func LoadAtlas(fsys fs.FS, name string) (*SpriteAtlas, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read atlas %q: %w", name, err)
+	}
+	a := &SpriteAtlas{}
+	if err := json.Unmarshal(data, a); err != nil {
+		return nil, fmt.Errorf("cannot parse atlas %q: %w", name, err)
+	}
+	data, err = fs.ReadFile(fsys, path.Join(path.Dir(name), a.Image))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read atlas image %q: %w", a.Image, err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode atlas image %q: %w", a.Image, err)
+	}
+	sheet := ebiten.NewImageFromImage(img)
+	for name, anim := range a.Animations {
+		if len(anim.Frames) == 0 {
+			return nil, fmt.Errorf("animation %q has no frames", name)
+		}
+		for _, f := range anim.Frames {
+			r := image.Rect(f.X, f.Y, f.X+f.W, f.Y+f.H)
+			if !r.In(sheet.Bounds()) {
+				return nil, fmt.Errorf("frame %v of %q is outside the atlas", f, name)
+			}
+			anim.images = append(anim.images, sheet.SubImage(r).(*ebiten.Image))
+		}
+	}
+	return a, nil
+}
+
+// Frame renvoie l'image de l'animation name au temps tick, nil si l'atlas ou
+// l'animation manque.
+func (a *SpriteAtlas) Frame(name string, tick float64) *ebiten.Image {
+	if a == nil {
+		return nil
+	}
+	anim, ok := a.Animations[name]
+	if !ok {
+		return nil
+	}
+	i := 0
+	if anim.FrameTicks > 0 {
+		i = int(tick) / anim.FrameTicks % len(anim.images)
+	}
+	return anim.images[i]
+}
+
+// DrawSprite dessine l'animation name étirée sur le rectangle (x, y, w, h),
+// tournée de angle autour de son centre et teintée par clr. Elle renvoie
+// false si le sprite manque, pour que l'appelant dessine le rectangle.
+func DrawSprite(screen *ebiten.Image, name string, tick, x, y, w, h, angle float64, clr color.Color) bool {
+	img := atlas.Frame(name, tick)
+	if img == nil {
+		return false
+	}
+	b := img.Bounds()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(w/float64(b.Dx()), h/float64(b.Dy()))
+	op.GeoM.Translate(-w/2, -h/2)
+	op.GeoM.Rotate(angle)
+	op.GeoM.Translate(x+w/2, y+h/2)
+	op.ColorScale.ScaleWithColor(clr)
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(img, op)
+	return true
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+	"testing/fstest"
+)
+
+// TestMissingAtlasFallsBack vérifie qu'un atlas absent ou illisible laisse
+// atlas à nil, et que les objets sont alors dessinés en rectangles.
+func TestMissingAtlasFallsBack(t *testing.T) {
+	for name, fsys := range map[string]fstest.MapFS{
+		"missing":  {},
+		"corrupt":  {"sprites/atlas.json": {Data: []byte("{")}},
+		"no image": {"sprites/atlas.json": {Data: []byte(`{"image": "atlas.png"}`)}},
+	} {
+		a, err := LoadAtlas(fsys, "sprites/atlas.json")
+		if err == nil || a != nil {
+			t.Errorf("%s: atlas loaded", name)
+		}
+	}
+	saved := atlas
+	defer func() { atlas = saved }()
+	atlas = nil
+	if DrawSprite(nil, "barrel", 0, 0, 0, 100, 50, 0, color.White) {
+		t.Fatal("sprite drawn without an atlas")
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	TeleportCooldownTicks = 30
+	WarpTicks             = 20 // durée de l'apparition du joueur à la sortie
+)
+
+// TeleporterBarrel envoie le joueur vers le téléporteur d'ID Link. Deux
+// téléporteurs liés l'un à l'autre forment une paire dans les deux sens ; un
+// téléporteur sans Link n'est qu'une sortie, sauf s'il a une sortie fixe
+// (ToX, ToY) comme dans les premiers niveaux.
+type TeleporterBarrel struct {
+	*Barrel
+	ID        string
+	Link      string
+	ToX, ToY  float64
+	Redirect  bool    // la sortie réoriente la vitesse selon ExitAngle
+	ExitAngle float64 // direction de sortie en radians, 0 = vers la droite
+	cooldown  float64
+	warp      float64
+	spin      float64
+}
+
+func NewTeleporterBarrel(x, y, toX, toY float64) *TeleporterBarrel {
+	return &TeleporterBarrel{Barrel: NewBarrel(x, y), ToX: toX, ToY: toY}
+}
+
+// NewTeleporterPair crée deux téléporteurs liés. Si oneWay, b n'est qu'une sortie.
+func NewTeleporterPair(id string, ax, ay, bx, by float64, oneWay bool) (*TeleporterBarrel, *TeleporterBarrel) {
+	a := &TeleporterBarrel{Barrel: NewBarrel(ax, ay), ID: id + ".a", Link: id + ".b"}
+	b := &TeleporterBarrel{Barrel: NewBarrel(bx, by), ID: id + ".b"}
+	if !oneWay {
+		b.Link = a.ID
+	}
+	return a, b
+}
+
+// WithExit fait sortir le joueur de ce téléporteur dans la direction angle.
+func (t *TeleporterBarrel) WithExit(angle float64) *TeleporterBarrel {
+	t.Redirect = true
+	t.ExitAngle = angle
+	return t
+}
+
+// Entrance indique si le joueur peut entrer dans ce téléporteur.
+func (t *TeleporterBarrel) Entrance() bool {
+	return t.Link != "" || t.ToX != 0 || t.ToY != 0
+}
+
+func (g *Game) FindTeleporter(id string) *TeleporterBarrel {
+	for _, e := range g.Entities {
+		if t, ok := e.(*TeleporterBarrel); ok && t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func (t *TeleporterBarrel) Update(g *Game) {
+	t.Barrel.Update(g)
+	dt := g.TimeScale()
+	t.spin += 0.08 * dt
+	if t.cooldown > 0 {
+		t.cooldown -= dt
+	}
+	if t.warp > 0 {
+		t.warp -= dt
+	}
+}
+
+func (t *TeleporterBarrel) CollidePlayer(g *Game) {
+	if t.Entrance() && t.cooldown <= 0 && t.Touches(g) && !g.ChangeLevelAnimation {
+		t.teleport(g)
+		return
+	}
+	t.land(g, false)
+}
+
+func (t *TeleporterBarrel) teleport(g *Game) {
+	exit := g.FindTeleporter(t.Link)
+	if t.Link != "" && exit == nil {
+		return
+	}
+	g.Emit(&WarpInEmitter, g.PlayerX, g.PlayerY, 0)
+	g.PlayAt(g.teleportSound, t.X+t.W/2)
+	t.cooldown = TeleportCooldownTicks
+	t.warp = WarpTicks
+	if exit == nil {
+		// ancienne sortie fixe
+		g.PlayerX, g.PlayerY = t.ToX, t.ToY
+	} else {
+		exit.cooldown = TeleportCooldownTicks
+		exit.warp = WarpTicks
+		speed := math.Hypot(g.PlayerVX, g.PlayerVY)
+		if exit.Redirect {
+			g.PlayerVX = math.Cos(exit.ExitAngle) * speed
+			g.PlayerVY = math.Sin(exit.ExitAngle) * speed
+		}
+		g.PlayerX, g.PlayerY = exit.ExitPoint(g.PlayerVX, g.PlayerVY)
+	}
+	g.WarpTimer = WarpTicks
+	g.Emit(&WarpOutEmitter, g.PlayerX, g.PlayerY, 0)
+}
+
+// ExitPoint renvoie où placer le joueur qui sort en allant dans la direction
+// (vx, vy) : juste assez loin du centre pour ne plus toucher le baril.
+func (t *TeleporterBarrel) ExitPoint(vx, vy float64) (float64, float64) {
+	dx, dy := math.Cos(t.ExitAngle), math.Sin(t.ExitAngle)
+	if v := math.Hypot(vx, vy); v > 0 {
+		dx, dy = vx/v, vy/v
+	}
+	// distance pour dégager le cercle du rectangle sur l'un des deux axes
+	d := math.Inf(1)
+	if dx != 0 {
+		d = (t.W/2 + PlayerR + 1) / math.Abs(dx)
+	}
+	if dy != 0 {
+		d = math.Min(d, (t.H/2+PlayerR+1)/math.Abs(dy))
+	}
+	return t.X + t.W/2 + dx*d, t.Y + t.H/2 + dy*d
+}
+
+func (t *TeleporterBarrel) Draw(screen *ebiten.Image, g *Game) {
+	t.Barrel.Draw(screen, g)
+	cx, cy := t.X+t.W/2, t.Y+t.H/2
+	clr := RoleColor(RoleTeleporter)
+	if !t.Entrance() {
+		clr.A = 120 // sortie seulement
+	}
+	DrawPortal(screen, cx, cy, 30, 15, t.spin, clr)
+	if t.Redirect {
+		// flèche de la direction de sortie
+		ax, ay := cx+math.Cos(t.ExitAngle)*45, cy+math.Sin(t.ExitAngle)*45
+		ebitenutil.DrawCircle(screen, ax, ay, 4, clr)
+	}
+	if t.Link == "" && t.Entrance() {
+		DrawPortal(screen, t.ToX, t.ToY, 12, 12, t.spin, clr)
+	}
+	// éclair qui se referme après un passage
+	if t.warp > 0 {
+		k := t.warp / WarpTicks
+		flash := RoleColor(RoleTeleporter)
+		flash.A = uint8(200 * k)
+		ebitenutil.DrawCircle(screen, cx, cy, 40*k, flash)
+	}
+}
+
+// DrawPortal dessine un tourbillon de points en ellipse autour de (x, y).
+func DrawPortal(screen *ebiten.Image, x, y, rx, ry, spin float64, clr color.RGBA) {
+	for i := 0; i < 12; i++ {
+		a := spin + float64(i)*2*math.Pi/12
+		r := 1 - float64(i%3)*0.25
+		ebitenutil.DrawCircle(screen, x+math.Cos(a)*rx*r, y+math.Sin(a)*ry*r, 3, clr)
+	}
+}
+
+func (t *TeleporterBarrel) Serialize() EntityData {
+	return serializeEntity("teleporter", t)
+}
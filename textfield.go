@@ -0,0 +1,211 @@
+package main
+
+import (
+	"image/color"
+	"log"
+	"slices"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	KeyRepeatDelay    = 30 // ticks avant la première répétition
+	KeyRepeatInterval = 4  // ticks entre deux répétitions
+)
+
+// KeyRepeat indique si la touche vient d'être enfoncée ou se répète parce
+// qu'elle est tenue.
+func KeyRepeat(key ebiten.Key) bool {
+	d := inpututil.KeyPressDuration(key)
+	return d == 1 || d >= KeyRepeatDelay && (d-KeyRepeatDelay)%KeyRepeatInterval == 0
+}
+
+// TextField est un champ de saisie d'une ligne. Le texte est édité rune par
+// rune, les caractères refusés par Allowed ou au-delà de MaxLen sont ignorés
+// et Validate donne l'erreur à afficher sous le champ pendant la saisie.
+type TextField struct {
+	MaxLen   int                 // en runes, 0 = sans limite
+	Allowed  func(rune) bool     // nil = tout caractère imprimable
+	Validate func(string) string // identifiant du message d'erreur, "" si valide
+	Masked   bool                // affiche des * (codes)
+	// messages des caractères refusés
+	CharsetMsg, LengthMsg string
+
+	text     []rune
+	cursor   int
+	touched  bool // le joueur a tapé ou validé : les erreurs s'affichent
+	rejected string
+	flash    int // ticks restants du message de caractère refusé
+	blink    int
+	chars    []rune
+	paste    <-chan Paste // collage en attente du presse-papiers
+}
+
+// NewNameField crée le champ du pseudo : lettres et chiffres, 7 au plus.
+func NewNameField() *TextField {
+	return &TextField{
+		MaxLen:     7,
+		Allowed:    func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) },
+		CharsetMsg: MsgNameNotAlnum,
+		LengthMsg:  MsgNameTooLong,
+		Validate: func(s string) string {
+			if s == "" {
+				return MsgNameEmpty
+			}
+			return ""
+		},
+	}
+}
+
+// NewCodeField crée le champ masqué du code secret.
+func NewCodeField() *TextField {
+	return &TextField{
+		MaxLen:     16,
+		Allowed:    func(r rune) bool { return unicode.IsPrint(r) && !unicode.IsSpace(r) },
+		Masked:     true,
+		CharsetMsg: MsgCodeCharset,
+		LengthMsg:  MsgCodeTooLong,
+	}
+}
+
+func (f *TextField) String() string {
+	return string(f.text)
+}
+
+func (f *TextField) SetText(s string) {
+	f.text = []rune(s)
+	f.cursor = len(f.text)
+}
+
+// Error renvoie le message d'erreur à afficher, "" s'il n'y en a pas.
+func (f *TextField) Error() string {
+	if f.flash > 0 {
+		return f.rejected
+	}
+	if !f.touched || f.Validate == nil {
+		return ""
+	}
+	return f.Validate(f.String())
+}
+
+// insert ajoute les runes acceptées au curseur.
+func (f *TextField) insert(rs []rune) {
+	for _, r := range rs {
+		allowed := unicode.IsPrint(r)
+		if f.Allowed != nil {
+			allowed = f.Allowed(r)
+		}
+		switch {
+		case !allowed:
+			f.reject(f.CharsetMsg)
+		case f.MaxLen > 0 && len(f.text) >= f.MaxLen:
+			f.reject(f.LengthMsg)
+		default:
+			f.text = slices.Insert(f.text, f.cursor, r)
+			f.cursor++
+		}
+	}
+}
+
+func (f *TextField) reject(msg string) {
+	if msg == "" {
+		return
+	}
+	f.rejected = msg
+	f.flash = 80
+}
+
+// Update traite le clavier de cette frame et renvoie true quand le joueur
+// valide un texte correct avec Confirm (Entrée par défaut).
+func (f *TextField) Update() bool {
+	f.blink++
+	if f.flash > 0 {
+		f.flash--
+	}
+	f.chars = ebiten.AppendInputChars(f.chars[:0])
+	if len(f.chars) > 0 {
+		f.touched = true
+		f.insert(f.chars)
+	}
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	if ctrl && inpututil.IsKeyJustPressed(ebiten.KeyV) && f.paste == nil {
+		f.paste = ReadClipboardAsync()
+	}
+	select {
+	case p := <-f.paste:
+		f.paste = nil
+		if p.Err != nil {
+			log.Printf("paste: %v", p.Err)
+		} else {
+			f.touched = true
+			f.insert([]rune(p.Text))
+		}
+	default:
+	}
+	switch {
+	case KeyRepeat(ebiten.KeyBackspace):
+		f.Backspace()
+	case KeyRepeat(ebiten.KeyDelete) && f.cursor < len(f.text):
+		f.text = slices.Delete(f.text, f.cursor, f.cursor+1)
+		f.touched = true
+	case KeyRepeat(ebiten.KeyArrowLeft) && f.cursor > 0:
+		f.cursor--
+	case KeyRepeat(ebiten.KeyArrowRight) && f.cursor < len(f.text):
+		f.cursor++
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		f.cursor = 0
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		f.cursor = len(f.text)
+	}
+	// à la manette, Confirm tape la touche du clavier à l'écran (voir Keyboard)
+	if IsActionJustPressed(ActionConfirm) && !IsActionFromPad(ActionConfirm) {
+		return f.Submit()
+	}
+	return false
+}
+
+// Type ajoute r au curseur, comme une frappe au clavier.
+func (f *TextField) Type(r rune) {
+	f.touched = true
+	f.insert([]rune{r})
+}
+
+// Backspace efface la rune avant le curseur.
+func (f *TextField) Backspace() {
+	if f.cursor == 0 {
+		return
+	}
+	f.text = slices.Delete(f.text, f.cursor-1, f.cursor)
+	f.cursor--
+	f.touched = true
+}
+
+// Submit valide la saisie et renvoie true si le texte est correct.
+func (f *TextField) Submit() bool {
+	f.touched = true
+	return f.Validate == nil || f.Validate(f.String()) == ""
+}
+
+// shown renvoie le texte tel qu'il s'affiche.
+func (f *TextField) shown() []rune {
+	if !f.Masked {
+		return f.text
+	}
+	return []rune(string(slices.Repeat([]byte{'*'}, len(f.text))))
+}
+
+// Draw écrit label suivi du texte, avec le curseur clignotant, et l'erreur
+// éventuelle en dessous.
+func (f *TextField) Draw(h HUD, label string, size float64, anchor Anchor, dx, dy float64, clr color.Color) {
+	shown := f.shown()
+	h.Text(label+string(shown), size, anchor, dx, dy, clr)
+	if f.blink/30%2 == 0 {
+		x := dx + h.TextWidth(label+string(shown[:f.cursor]), size)
+		h.Rect(anchor, x, dy-size/2, 3, size, clr)
+	}
+	if msg := f.Error(); msg != "" {
+		h.Text(T(msg), size*2/3, anchor, dx, dy+size*1.2, RoleColor(RoleHUDText))
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// ColorRole est l'usage d'une couleur à l'écran. Le rendu demande une couleur
+// par rôle au thème courant au lieu de coder des valeurs en dur.
+type ColorRole int
+
+const (
+	RoleBackground ColorRole = iota
+	RoleHUDText
+	RolePlayer
+	RoleBarrel
+	RoleMagicBarrel
+	RoleFragileHot // baril fragile que le joueur touche
+	RoleCheckpoint
+	RoleTeleporter
+	RoleObstacle
+	RoleBouncer
+	RoleCoin
+	RoleStar
+	RolePatroller
+	RoleChaser
+	RoleTurret
+	RoleProjectile
+	RoleGravityZone
+	RoleWindZone
+	RoleDragZone
+	RoleGate
+	RoleSwitchOff
+	RoleSwitchOn
+	RoleCue // motifs qui distinguent les objets sans la couleur
+	roleCount
+)
+
+// Palette associe une couleur à chaque rôle.
+type Palette struct {
+	Name   string
+	Colors [roleCount]color.RGBA
+}
+
+var Themes = []*Palette{
+	{Name: "default", Colors: [roleCount]color.RGBA{
+		RoleBackground:  {221, 182, 242, 125},
+		RoleHUDText:     {222, 49, 99, 0},
+		RolePlayer:      {255, 255, 0, 255},
+		RoleBarrel:      {139, 69, 19, 255},
+		RoleMagicBarrel: {138, 43, 226, 255},
+		RoleFragileHot:  {255, 0, 0, 255},
+		RoleCheckpoint:  {60, 220, 120, 255},
+		RoleTeleporter:  {80, 220, 255, 255},
+		RoleObstacle:    {178, 34, 34, 255},
+		RoleBouncer:     {58, 110, 165, 255},
+		RoleCoin:        {255, 215, 0, 255},
+		RoleStar:        {255, 240, 120, 255},
+		RolePatroller:   {200, 40, 40, 255},
+		RoleChaser:      {150, 30, 120, 255},
+		RoleTurret:      {70, 70, 80, 255},
+		RoleProjectile:  {255, 140, 0, 255},
+		RoleGravityZone: {120, 90, 200, 255},
+		RoleWindZone:    {200, 230, 255, 255},
+		RoleDragZone:    {90, 160, 120, 255},
+		RoleGate:        {90, 90, 110, 255},
+		RoleSwitchOff:   {120, 120, 120, 255},
+		RoleSwitchOn:    {80, 200, 80, 255},
+		RoleCue:         {0, 0, 0, 110},
+	}},
+	{Name: "high contrast", Colors: [roleCount]color.RGBA{
+		RoleBackground:  {0, 0, 0, 255},
+		RoleHUDText:     {255, 255, 255, 255},
+		RolePlayer:      {255, 255, 0, 255},
+		RoleBarrel:      {230, 230, 230, 255},
+		RoleMagicBarrel: {0, 255, 255, 255},
+		RoleFragileHot:  {255, 0, 255, 255},
+		RoleCheckpoint:  {0, 255, 0, 255},
+		RoleTeleporter:  {0, 160, 255, 255},
+		RoleObstacle:    {255, 40, 0, 255},
+		RoleBouncer:     {40, 100, 255, 255},
+		RoleCoin:        {255, 220, 0, 255},
+		RoleStar:        {255, 255, 160, 255},
+		RolePatroller:   {255, 0, 80, 255},
+		RoleChaser:      {255, 100, 255, 255},
+		RoleTurret:      {160, 160, 160, 255},
+		RoleProjectile:  {255, 140, 0, 255},
+		RoleGravityZone: {180, 120, 255, 255},
+		RoleWindZone:    {200, 255, 255, 255},
+		RoleDragZone:    {0, 255, 120, 255},
+		RoleGate:        {200, 200, 200, 255},
+		RoleSwitchOff:   {128, 128, 128, 255},
+		RoleSwitchOn:    {120, 255, 120, 255},
+		RoleCue:         {0, 0, 0, 200},
+	}},
+	// Les thèmes daltoniens reprennent la palette d'Okabe et Ito : les rôles
+	// qui se ressemblent pour chaque type de daltonisme sont séparés par la
+	// luminosité et par les motifs.
+	{Name: "deuteranopia", Colors: [roleCount]color.RGBA{
+		RoleBackground:  {200, 200, 220, 125},
+		RoleHUDText:     {20, 20, 20, 255},
+		RolePlayer:      {240, 228, 66, 255},
+		RoleBarrel:      {140, 100, 60, 255},
+		RoleMagicBarrel: {204, 121, 167, 255},
+		RoleFragileHot:  {230, 159, 0, 255},
+		RoleCheckpoint:  {0, 158, 115, 255},
+		RoleTeleporter:  {86, 180, 233, 255},
+		RoleObstacle:    {213, 94, 0, 255},
+		RoleBouncer:     {0, 60, 130, 255},
+		RoleCoin:        {255, 200, 40, 255},
+		RoleStar:        {255, 250, 180, 255},
+		RolePatroller:   {160, 60, 0, 255},
+		RoleChaser:      {150, 70, 120, 255},
+		RoleTurret:      {60, 60, 60, 255},
+		RoleProjectile:  {255, 190, 120, 255},
+		RoleGravityZone: {0, 114, 178, 255},
+		RoleWindZone:    {220, 220, 220, 255},
+		RoleDragZone:    {230, 180, 210, 255},
+		RoleGate:        {80, 80, 80, 255},
+		RoleSwitchOff:   {120, 120, 120, 255},
+		RoleSwitchOn:    {170, 220, 245, 255},
+		RoleCue:         {0, 0, 0, 130},
+	}},
+	{Name: "protanopia", Colors: [roleCount]color.RGBA{
+		RoleBackground:  {200, 210, 230, 125},
+		RoleHUDText:     {20, 20, 20, 255},
+		RolePlayer:      {240, 228, 66, 255},
+		RoleBarrel:      {120, 100, 70, 255},
+		RoleMagicBarrel: {204, 121, 167, 255},
+		RoleFragileHot:  {255, 140, 80, 255},
+		RoleCheckpoint:  {0, 158, 115, 255},
+		RoleTeleporter:  {86, 180, 233, 255},
+		RoleObstacle:    {230, 159, 0, 255}, // le rouge paraît sombre aux protanopes
+		RoleBouncer:     {0, 60, 130, 255},
+		RoleCoin:        {255, 200, 40, 255},
+		RoleStar:        {255, 250, 180, 255},
+		RolePatroller:   {170, 110, 0, 255},
+		RoleChaser:      {150, 70, 120, 255},
+		RoleTurret:      {60, 60, 60, 255},
+		RoleProjectile:  {255, 190, 120, 255},
+		RoleGravityZone: {0, 114, 178, 255},
+		RoleWindZone:    {220, 220, 220, 255},
+		RoleDragZone:    {230, 180, 210, 255},
+		RoleGate:        {80, 80, 80, 255},
+		RoleSwitchOff:   {120, 120, 120, 255},
+		RoleSwitchOn:    {170, 220, 245, 255},
+		RoleCue:         {0, 0, 0, 130},
+	}},
+	{Name: "tritanopia", Colors: [roleCount]color.RGBA{
+		RoleBackground:  {230, 200, 200, 125},
+		RoleHUDText:     {204, 0, 80, 255},
+		RolePlayer:      {255, 120, 160, 255}, // le jaune se confond avec le blanc
+		RoleBarrel:      {120, 80, 60, 255},
+		RoleMagicBarrel: {0, 158, 115, 255},
+		RoleFragileHot:  {213, 94, 0, 255},
+		RoleCheckpoint:  {0, 110, 80, 255},
+		RoleTeleporter:  {0, 200, 200, 255},
+		RoleObstacle:    {200, 0, 40, 255},
+		RoleBouncer:     {40, 40, 40, 255},
+		RoleCoin:        {255, 80, 120, 255},
+		RoleStar:        {255, 200, 210, 255},
+		RolePatroller:   {140, 0, 30, 255},
+		RoleChaser:      {120, 0, 80, 255},
+		RoleTurret:      {90, 90, 90, 255},
+		RoleProjectile:  {240, 140, 80, 255},
+		RoleGravityZone: {90, 200, 170, 255},
+		RoleWindZone:    {240, 240, 240, 255},
+		RoleDragZone:    {204, 121, 167, 255},
+		RoleGate:        {70, 70, 70, 255},
+		RoleSwitchOff:   {130, 130, 130, 255},
+		RoleSwitchOn:    {170, 230, 210, 255},
+		RoleCue:         {0, 0, 0, 130},
+	}},
+}
+
+var theme = Themes[0]
+
+// RoleColor renvoie la couleur du rôle dans le thème courant.
+func RoleColor(r ColorRole) color.RGBA {
+	return theme.Colors[r]
+}
+
+// SetTheme choisit le thème par son nom, le thème par défaut s'il n'existe pas.
+func SetTheme(name string) {
+	theme = Themes[0]
+	for _, t := range Themes {
+		if t.Name == name {
+			theme = t
+		}
+	}
+}
+
+// NextTheme passe au thème suivant et renvoie son nom.
+func NextTheme() string {
+	for i, t := range Themes {
+		if t == theme {
+			theme = Themes[(i+1)%len(Themes)]
+			break
+		}
+	}
+	return theme.Name
+}
+
+// --- MOTIFS ---
+
+// DrawStripes hachure le rectangle (x, y, w, h) en diagonale, tous les gap px.
+func DrawStripes(screen *ebiten.Image, x, y, w, h, gap float64, clr color.Color) {
+	for d := gap / 2; d < w+h; d += gap {
+		// segment de la diagonale x+y = d coupé par le rectangle
+		x0, y0 := x+math.Min(d, w), y+math.Max(0, d-w)
+		x1, y1 := x+math.Max(0, d-h), y+math.Min(d, h)
+		vector.StrokeLine(screen, float32(x0), float32(y0), float32(x1), float32(y1), 2, clr, true)
+	}
+}
+
+// DrawChecker dessine une bande en damier, comme un drapeau d'arrivée.
+func DrawChecker(screen *ebiten.Image, x, y, w, h, size float64, clr color.Color) {
+	for i := 0.0; i*size < w; i++ {
+		for j := 0.0; j*size < h; j++ {
+			if int(i+j)%2 == 0 {
+				vector.DrawFilledRect(screen, float32(x+i*size), float32(y+j*size), float32(math.Min(size, w-i*size)), float32(math.Min(size, h-j*size)), clr, false)
+			}
+		}
+	}
+}
+
+// DrawCross dessine un X dans le rectangle (danger).
+func DrawCross(screen *ebiten.Image, x, y, w, h float64, clr color.Color) {
+	vector.StrokeLine(screen, float32(x+4), float32(y+4), float32(x+w-4), float32(y+h-4), 3, clr, true)
+	vector.StrokeLine(screen, float32(x+w-4), float32(y+4), float32(x+4), float32(y+h-4), 3, clr, true)
+}
+
+// DrawDashedRect dessine le contour du rectangle en pointillés.
+func DrawDashedRect(screen *ebiten.Image, x, y, w, h float64, clr color.Color) {
+	for i := 0.0; i < w; i += 10 {
+		l := float32(math.Min(5, w-i))
+		vector.DrawFilledRect(screen, float32(x+i), float32(y), l, 2, clr, false)
+		vector.DrawFilledRect(screen, float32(x+i), float32(y+h-2), l, 2, clr, false)
+	}
+	for j := 0.0; j < h; j += 10 {
+		l := float32(math.Min(5, h-j))
+		vector.DrawFilledRect(screen, float32(x), float32(y+j), 2, l, clr, false)
+		vector.DrawFilledRect(screen, float32(x+w-2), float32(y+j), 2, l, clr, false)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+// TestPaletteRolesDistinct vérifie que, dans chaque thème, deux rôles du jeu
+// n'ont jamais la même couleur (le fond, le HUD et les motifs mis à part).
+func TestPaletteRolesDistinct(t *testing.T) {
+	for _, p := range Themes {
+		seen := map[[3]uint8]ColorRole{}
+		for r := RolePlayer; r <= RoleSwitchOn; r++ {
+			c := p.Colors[r]
+			rgb := [3]uint8{c.R, c.G, c.B}
+			if other, ok := seen[rgb]; ok {
+				t.Errorf("%s: roles %d and %d share %v", p.Name, other, r, rgb)
+			}
+			seen[rgb] = r
+		}
+	}
+}
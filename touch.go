@@ -0,0 +1,144 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Touch est un doigt posé sur l'écran, en coordonnées du canvas. Il tient
+// l'action du bouton où il s'est posé, ActionLaunch ailleurs : toucher
+// l'écran n'importe où lance le joueur.
+type Touch struct {
+	X, Y   float64
+	Action Action
+}
+
+// TouchButton est une zone de l'écran qui déclenche Action quand on la touche.
+type TouchButton struct {
+	X, Y, W, H float64
+	Action     Action
+}
+
+// TouchPoint est la position d'un doigt relevée pour un tick.
+type TouchPoint struct {
+	ID   ebiten.TouchID
+	X, Y float64
+}
+
+// pollTouches relève les doigts posés, réels puis simulés.
+func (in *Input) pollTouches(toCanvas func(x, y int) (float64, float64)) []TouchPoint {
+	in.points = in.points[:0]
+	in.touchIDs = ebiten.AppendTouchIDs(in.touchIDs[:0])
+	for _, id := range in.touchIDs {
+		x, y := toCanvas(ebiten.TouchPosition(id))
+		in.points = append(in.points, TouchPoint{ID: id, X: x, Y: y})
+	}
+	return in.appendSimulated(in.points)
+}
+
+func (in *Input) appendSimulated(points []TouchPoint) []TouchPoint {
+	for id, p := range in.simulated {
+		points = append(points, TouchPoint{ID: id, X: p[0], Y: p[1]})
+	}
+	return points
+}
+
+// updateTouches suit les doigts d'un tick à l'autre. Un doigt garde l'action
+// choisie quand il s'est posé, même s'il glisse hors du bouton.
+func (in *Input) updateTouches(points []TouchPoint) {
+	in.started = in.started[:0]
+	if in.next == nil {
+		in.next = map[ebiten.TouchID]Touch{}
+	}
+	clear(in.next)
+	for _, p := range points {
+		t, ok := in.touches[p.ID]
+		if !ok {
+			t.Action = in.buttonAt(p.X, p.Y)
+			in.TouchUsed = true
+		}
+		t.X, t.Y = p.X, p.Y
+		in.next[p.ID] = t
+		if !ok {
+			in.started = append(in.started, t)
+		}
+	}
+	in.touches, in.next = in.next, in.touches
+}
+
+func (in *Input) buttonAt(x, y float64) Action {
+	for _, b := range in.Buttons {
+		if Within(x, y, b.X, b.Y, b.W, b.H) {
+			return b.Action
+		}
+	}
+	return ActionLaunch
+}
+
+// touching indique si un doigt tient l'action a.
+func (in *Input) touching(a Action) bool {
+	for _, t := range in.touches {
+		if t.Action == a {
+			return true
+		}
+	}
+	return false
+}
+
+// Tapped indique si un doigt vient de se poser dans le rectangle.
+func (in *Input) Tapped(x, y, w, h float64) bool {
+	for _, t := range in.started {
+		if Within(t.X, t.Y, x, y, w, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// JustTouched indique si un doigt vient de se poser n'importe où.
+func (in *Input) JustTouched() bool {
+	return len(in.started) > 0
+}
+
+// SimulateTouch pose ou déplace un doigt simulé, en coordonnées du canvas,
+// pris en compte au prochain Update. Les ids négatifs ne croisent pas ceux
+// des vrais doigts.
+func (in *Input) SimulateTouch(id ebiten.TouchID, x, y float64) {
+	if in.simulated == nil {
+		in.simulated = map[ebiten.TouchID][2]float64{}
+	}
+	in.simulated[id] = [2]float64{x, y}
+}
+
+// ReleaseTouch lève le doigt simulé id.
+func (in *Input) ReleaseTouch(id ebiten.TouchID) {
+	delete(in.simulated, id)
+}
+
+// mouseTouchID est le doigt simulé par la souris avec l'option -touch.
+const mouseTouchID ebiten.TouchID = -1
+
+// SimulateMouseTouch fait de la souris un doigt, pour essayer les commandes
+// tactiles sur ordinateur.
+func (in *Input) SimulateMouseTouch(d *Display) {
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		x, y := d.CursorPosition()
+		in.SimulateTouch(mouseTouchID, x, y)
+	} else {
+		in.ReleaseTouch(mouseTouchID)
+	}
+}
+
+// PauseButton renvoie le rectangle du bouton pause tactile dans le canvas.
+func PauseButton() (float64, float64, float64, float64) {
+	return ScreenW - 48, 4, 40, 34
+}
+
+// DrawPauseButton dessine le bouton pause : deux barres verticales.
+func DrawPauseButton(h HUD) {
+	x, y, w, ht := PauseButton()
+	h.Rect(AnchorTopLeft, x, y, w, ht, color.RGBA{0, 0, 0, 140})
+	h.Rect(AnchorTopLeft, x+w/2-10, y+7, 7, ht-14, color.White)
+	h.Rect(AnchorTopLeft, x+w/2+3, y+7, 7, ht-14, color.White)
+}
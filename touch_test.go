@@ -0,0 +1,69 @@
+package main
+
+import "testing"
+
+// touchTick avance l'entrée d'un tick avec les seuls doigts simulés.
+func touchTick() {
+	input.update([ActionCount]DevicePress{}, input.appendSimulated(nil))
+}
+
+func TestTapPauseButton(t *testing.T) {
+	input = Input{Buffer: DefaultInputBuffer}
+	x, y, w, h := PauseButton()
+	input.Buttons = []TouchButton{{X: x, Y: y, W: w, H: h, Action: ActionPause}}
+
+	input.SimulateTouch(-1, x+w/2, y+h/2)
+	touchTick()
+	if !IsActionJustPressed(ActionPause) {
+		t.Error("tap on the pause button does not press Pause")
+	}
+	if IsActionPressed(ActionLaunch) {
+		t.Error("tap on the pause button also launches")
+	}
+	if !input.TouchUsed {
+		t.Error("TouchUsed not set after a tap")
+	}
+
+	touchTick()
+	if IsActionJustPressed(ActionPause) || !IsActionPressed(ActionPause) {
+		t.Error("held finger should keep Pause held without a new press")
+	}
+
+	input.ReleaseTouch(-1)
+	touchTick()
+	if !IsActionJustReleased(ActionPause) {
+		t.Error("lifting the finger does not release Pause")
+	}
+}
+
+func TestTapElsewhereLaunches(t *testing.T) {
+	input = Input{Buffer: DefaultInputBuffer}
+	x, y, w, h := PauseButton()
+	input.Buttons = []TouchButton{{X: x, Y: y, W: w, H: h, Action: ActionPause}}
+
+	m := NewPauseMenu()
+	rx, ry, rw, rh := m.ItemRect(PauseRestart)
+	input.SimulateTouch(-1, rx+rw/2, ry+rh/2)
+	touchTick()
+	if !IsActionJustPressed(ActionLaunch) {
+		t.Error("tap away from buttons does not press Launch")
+	}
+	if IsActionPressed(ActionPause) {
+		t.Error("tap away from the pause button presses Pause")
+	}
+	if !input.Tapped(rx, ry, rw, rh) {
+		t.Error("tap not reported on the menu item under the finger")
+	}
+	if ox, oy, ow, oh := m.ItemRect(PauseResume); input.Tapped(ox, oy, ow, oh) {
+		t.Error("tap reported on another menu item")
+	}
+	if !ConsumeAction(ActionLaunch) {
+		t.Error("tap does not buffer a launch")
+	}
+
+	touchTick()
+	if input.Tapped(rx, ry, rw, rh) {
+		t.Error("a held finger is reported as a new tap")
+	}
+	input.ReleaseTouch(-1)
+}
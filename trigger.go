@@ -0,0 +1,254 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Trigger est implémenté par les entités qui envoient un signal aux portes.
+// Les signaux sont recalculés au début de chaque tick (voir UpdateSignals).
+type Trigger interface {
+	Signal() (id string, active bool)
+}
+
+// UpdateSignals allume les signaux des interrupteurs actifs.
+func (g *Game) UpdateSignals() {
+	if g.Signals == nil {
+		g.Signals = map[string]bool{}
+	}
+	clear(g.Signals)
+	for _, e := range g.Entities {
+		if t, ok := e.(Trigger); ok {
+			if id, active := t.Signal(); active {
+				g.Signals[id] = true
+			}
+		}
+	}
+}
+
+// Key est ramassée au contact et ouvre une porte verrouillée du même ID.
+// X et Y sont le centre de la clé.
+type Key struct {
+	X, Y  float64
+	ID    string
+	Color color.RGBA
+}
+
+func NewKey(x, y float64, id string, clr color.RGBA) *Key {
+	return &Key{X: x, Y: y, ID: id, Color: clr}
+}
+
+func (k *Key) Update(g *Game) {}
+
+func (k *Key) CollidePlayer(g *Game) {
+	if !CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, k.X-8, k.Y-12, 16, 24) || g.ChangeLevelAnimation {
+		return
+	}
+	if g.Keys == nil {
+		g.Keys = map[string]int{}
+	}
+	g.Keys[k.ID]++
+	g.PlayAt(g.CollectSound, k.X)
+	g.RemoveEntity(k)
+}
+
+func (k *Key) Draw(screen *ebiten.Image, g *Game) {
+	DrawKey(screen, k.X, k.Y, k.Color)
+}
+
+func (k *Key) Serialize() EntityData {
+	return serializeEntity("key", k)
+}
+
+// DrawKey dessine une petite clé centrée en (x, y).
+func DrawKey(screen *ebiten.Image, x, y float64, clr color.Color) {
+	ebitenutil.DrawCircle(screen, x, y-6, 6, clr)
+	ebitenutil.DrawRect(screen, x-2, y-2, 4, 14, clr)
+	ebitenutil.DrawRect(screen, x+2, y+6, 5, 3, clr)
+}
+
+type SwitchMode int
+
+const (
+	SwitchMomentary SwitchMode = iota // actif tant que le joueur le touche
+	SwitchToggle                      // chaque contact inverse l'état
+	SwitchTimed                       // actif Duration ticks après le contact
+)
+
+// Switch est une plaque de pression qui envoie le signal ID.
+type Switch struct {
+	X, Y, W, H float64
+	ID         string
+	Mode       SwitchMode
+	Duration   int
+	On         bool
+	touched    bool
+	timer      float64
+}
+
+func NewSwitch(x, y, w, h float64, id string, mode SwitchMode) *Switch {
+	return &Switch{X: x, Y: y, W: w, H: h, ID: id, Mode: mode}
+}
+
+func NewTimedSwitch(x, y, w, h float64, id string, duration int) *Switch {
+	s := NewSwitch(x, y, w, h, id, SwitchTimed)
+	s.Duration = duration
+	return s
+}
+
+func (s *Switch) Signal() (string, bool) {
+	return s.ID, s.On
+}
+
+func (s *Switch) Update(g *Game) {
+	if s.Mode == SwitchTimed && s.On {
+		s.timer -= g.TimeScale()
+		if s.timer <= 0 {
+			s.On = false
+		}
+	}
+}
+
+func (s *Switch) CollidePlayer(g *Game) {
+	touching := CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, s.X, s.Y, s.W, s.H)
+	pressed := touching && !s.touched
+	s.touched = touching
+	switch s.Mode {
+	case SwitchMomentary:
+		s.On = touching
+	case SwitchToggle:
+		if pressed {
+			s.On = !s.On
+		}
+	case SwitchTimed:
+		if pressed {
+			s.On = true
+			s.timer = float64(s.Duration)
+		}
+	}
+	if pressed {
+		g.hitSound.Rewind()
+		g.hitSound.Play()
+	}
+}
+
+func (s *Switch) Draw(screen *ebiten.Image, g *Game) {
+	clr := RoleColor(RoleSwitchOff)
+	h := s.H
+	if s.On {
+		clr = RoleColor(RoleSwitchOn)
+		h = s.H / 2 // plaque enfoncée
+	}
+	ebitenutil.DrawRect(screen, s.X, s.Y+s.H-h, s.W, h, clr)
+	if s.Mode == SwitchTimed && s.On {
+		DrawCountdownRing(screen, s.X+s.W/2, s.Y-14, 8, s.timer/float64(s.Duration), clr)
+	}
+}
+
+func (s *Switch) Serialize() EntityData {
+	return serializeEntity("switch", s)
+}
+
+// Gate bloque le passage comme un mur tant qu'elle est fermée. Elle
+// s'ouvre quand le signal ID est actif (fermée si Inverted), ou pour de bon
+// quand le joueur la touche avec une clé KeyID.
+type Gate struct {
+	X, Y, W, H float64
+	ID         string
+	Inverted   bool
+	KeyID      string
+	KeyColor   color.RGBA
+	Unlocked   bool
+	open       float64 // 0 = fermée, 1 = ouverte
+}
+
+func NewGate(x, y, w, h float64, id string) *Gate {
+	return &Gate{X: x, Y: y, W: w, H: h, ID: id}
+}
+
+func NewLock(x, y, w, h float64, keyID string, clr color.RGBA) *Gate {
+	return &Gate{X: x, Y: y, W: w, H: h, KeyID: keyID, KeyColor: clr}
+}
+
+// ClosedRect renvoie la partie de la porte encore fermée (elle glisse vers le haut).
+func (gt *Gate) ClosedRect() (float64, float64, float64, float64) {
+	return gt.X, gt.Y, gt.W, gt.H * (1 - gt.open)
+}
+
+// Opening indique si la porte est ouverte ou en train de s'ouvrir.
+func (gt *Gate) Opening(g *Game) bool {
+	return gt.Unlocked || gt.ID != "" && g.Signals[gt.ID] != gt.Inverted
+}
+
+func (gt *Gate) Update(g *Game) {
+	target := 0.0
+	if gt.Opening(g) {
+		target = 1
+	}
+	step := 0.08 * g.TimeScale()
+	if gt.open < target {
+		gt.open = math.Min(target, gt.open+step)
+	} else {
+		gt.open = math.Max(target, gt.open-step)
+	}
+}
+
+// CollidePlayer ouvre la serrure si le joueur a la clé. Une porte fermée est
+// un mur : le joueur, arrêté contre elle par SweepRect, rebondit dessus.
+func (gt *Gate) CollidePlayer(g *Game) {
+	x, y, w, h := gt.ClosedRect()
+	if h <= 0 || gt.Opening(g) || !CircleRectCollision(g.PlayerX, g.PlayerY, PlayerR, x, y, w, h) {
+		return
+	}
+	if gt.KeyID != "" && g.Keys[gt.KeyID] > 0 {
+		g.Keys[gt.KeyID]--
+		gt.Unlocked = true
+		g.NameConfirmSound.Rewind()
+		g.NameConfirmSound.Play()
+		return
+	}
+	// normale depuis le point de la porte le plus proche du joueur
+	nx := g.PlayerX - math.Max(x, math.Min(g.PlayerX, x+w))
+	ny := g.PlayerY - math.Max(y, math.Min(g.PlayerY, y+h))
+	if nx == 0 && ny == 0 {
+		// centre dans la porte : on renvoie le joueur d'où il vient
+		nx, ny = -g.PlayerVX, -g.PlayerVY
+	}
+	d := math.Hypot(nx, ny)
+	if d == 0 {
+		return
+	}
+	nx, ny = nx/d, ny/d
+	if dot := g.PlayerVX*nx + g.PlayerVY*ny; dot < 0 {
+		g.PlayerVX -= 2 * dot * nx
+		g.PlayerVY -= 2 * dot * ny
+		g.hitSound.Rewind()
+		g.hitSound.Play()
+	}
+}
+
+func (gt *Gate) SweepRect(g *Game) (float64, float64, float64, float64, bool) {
+	x, y, w, h := gt.ClosedRect()
+	return x, y, w, h, h > 0
+}
+
+func (gt *Gate) Draw(screen *ebiten.Image, g *Game) {
+	x, y, w, h := gt.ClosedRect()
+	if h <= 0 {
+		return
+	}
+	ebitenutil.DrawRect(screen, x, y, w, h, RoleColor(RoleGate))
+	if gt.KeyID != "" {
+		// serrure de la couleur de la clé
+		DrawKey(screen, x+w/2, y+h/2, gt.KeyColor)
+	} else if !gt.Inverted {
+		ebitenutil.DrawRect(screen, x+w/2-2, y, 4, h, color.RGBA{255, 200, 0, 255})
+	}
+}
+
+func (gt *Gate) Serialize() EntityData {
+	return serializeEntity("gate", gt)
+}